@@ -5,18 +5,39 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/machinebox/graphql"
 	errorsHandler "shell.com/terraform-provider-wiz/errors"
 )
 
+// tokenRefreshMargin is how long before its expiry an access token is
+// refreshed, so that requests in flight never carry a lapsed token. Short
+// lived tokens are refreshed halfway through their lifetime instead.
+const tokenRefreshMargin = 5 * time.Minute
+
 type Client struct {
-	AccessToken string
-	Graphql     *graphql.Client
+	Graphql *GraphQLClient
+
+	credentials ClientCredentials
+	// accessToken is only read through token, which refreshes it first when
+	// needed.
+	accessToken string
+	// tokenRefreshAt is the zero time when the auth server did not report an
+	// expiry, in which case the token is only renewed after a 401.
+	tokenRefreshAt time.Time
+	// tokenMutex guards accessToken and tokenRefreshAt, which are refreshed
+	// while resources are being operated on concurrently.
+	tokenMutex sync.Mutex
+
+	// requestTimeout bounds every API request, including its retries. Zero
+	// means requests are only bounded by the caller's context.
+	requestTimeout time.Duration
 }
 
 func UnmarshalTokenResponse(data []byte) (TokenResponse, error) {
@@ -25,109 +46,321 @@ func UnmarshalTokenResponse(data []byte) (TokenResponse, error) {
 	return r, err
 }
 
-func AuthLoginRequest(credentials ClientCredentials) *http.Request {
-	apiUrl := "https://auth.wiz.io"
-	resource := "/oauth/token"
+func AuthLoginRequest(ctx context.Context, credentials ClientCredentials) *http.Request {
 	data := url.Values{}
 
 	data.Set("grant_type", "client_credentials")
 	data.Set("client_id", credentials.ClientID)
 	data.Set("client_secret", credentials.ClientSecret)
-	data.Set("audience", "beyond-api")
+	data.Set("audience", credentials.AuthAudience)
 
-	u, _ := url.ParseRequestURI(apiUrl)
-	u.Path = resource
-	urlStr := u.String()
-
-	r, _ := http.NewRequest(http.MethodPost, urlStr, strings.NewReader(data.Encode())) // URL-encoded payload
+	r, _ := http.NewRequestWithContext(ctx, http.MethodPost, credentials.AuthURL, strings.NewReader(data.Encode())) // URL-encoded payload
 	return r
 
 }
 
-func DoLogin(authLoginRequest *http.Request) string {
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func DoLogin(authLoginRequest *http.Request) (TokenResponse, error) {
 	client := &http.Client{}
 	authLoginRequest.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	authURL := authLoginRequest.URL.String()
 
 	resp, err := client.Do(authLoginRequest)
 
 	if err != nil {
-		panic(fmt.Sprintf("Ran into with initial authentication: %s", err))
+		return TokenResponse{}, &errorsHandler.AuthError{URL: authURL, Kind: errorsHandler.ErrAuthServerUnreachable, Err: err}
 	}
 	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body) // response body is []byte
+	body, err := io.ReadAll(resp.Body) // response body is []byte
+	if err != nil {
+		return TokenResponse{}, &errorsHandler.AuthError{URL: authURL, Kind: errorsHandler.ErrAuthServerUnreachable, StatusCode: resp.StatusCode, Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		authErr := &errorsHandler.AuthError{URL: authURL, Kind: errorsHandler.ErrUnexpectedAuthResponse, StatusCode: resp.StatusCode}
+
+		var oauthErr oauthErrorResponse
+		if json.Unmarshal(body, &oauthErr) == nil {
+			authErr.OAuthError = oauthErr.Error
+			authErr.OAuthErrorDescription = oauthErr.ErrorDescription
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden,
+			oauthErr.Error == "invalid_client" || oauthErr.Error == "unauthorized_client" || oauthErr.Error == "access_denied":
+			authErr.Kind = errorsHandler.ErrInvalidCredentials
+		}
+		return TokenResponse{}, authErr
+	}
 
 	tokenResponse, err := UnmarshalTokenResponse(body)
 	if err != nil {
-		panic(fmt.Sprintf("Ran into when unmarshalling token response: %s", err))
+		return TokenResponse{}, &errorsHandler.AuthError{URL: authURL, Kind: errorsHandler.ErrUnexpectedAuthResponse, StatusCode: resp.StatusCode, Err: err}
+	}
+	if tokenResponse.AccessToken == "" {
+		return TokenResponse{}, &errorsHandler.AuthError{URL: authURL, Kind: errorsHandler.ErrUnexpectedAuthResponse, StatusCode: resp.StatusCode, Err: errors.New("no access token in response")}
 	}
 
-	return tokenResponse.AccessToken
+	return tokenResponse, nil
 
 }
 
-func CreateClient(config ClientConfig) (*Client, error) {
+func CreateClient(ctx context.Context, config ClientConfig) (*Client, error) {
 
 	credentials, err := GetCredentials(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credentials, error: %s", err.Error())
 	}
 
-	authLoginRequest := AuthLoginRequest(credentials)
+	client := &Client{
+		Graphql:        NewGraphQLClient(credentials.Endpoint, newRetryTransport(config.Transport, config.MaxRetries, config.RetryMaxWait)),
+		credentials:    credentials,
+		requestTimeout: config.RequestTimeout,
+	}
+
+	client.tokenMutex.Lock()
+	defer client.tokenMutex.Unlock()
+	if err := client.login(ctx); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// login fetches a new access token. The caller must hold tokenMutex.
+func (client *Client) login(ctx context.Context) error {
+	tokenResponse, err := DoLogin(AuthLoginRequest(ctx, client.credentials))
+	if err != nil {
+		return err
+	}
+
+	client.accessToken = tokenResponse.AccessToken
+	client.tokenRefreshAt = time.Time{}
+	if tokenResponse.ExpiresIn > 0 {
+		lifetime := time.Duration(tokenResponse.ExpiresIn) * time.Second
+		margin := tokenRefreshMargin
+		if margin > lifetime/2 {
+			margin = lifetime / 2
+		}
+		client.tokenRefreshAt = time.Now().Add(lifetime - margin)
+	}
+	return nil
+}
+
+// token returns a valid access token, logging in again when the current one
+// is about to expire.
+func (client *Client) token(ctx context.Context) (string, error) {
+	client.tokenMutex.Lock()
+	defer client.tokenMutex.Unlock()
+
+	if client.accessToken == "" || (!client.tokenRefreshAt.IsZero() && time.Now().After(client.tokenRefreshAt)) {
+		if err := client.login(ctx); err != nil {
+			return "", err
+		}
+	}
+	return client.accessToken, nil
+}
+
+// invalidateToken forces the next call to token to log in again, unless
+// another request already replaced the rejected token in the meantime.
+func (client *Client) invalidateToken(rejected string) {
+	client.tokenMutex.Lock()
+	defer client.tokenMutex.Unlock()
 
-	return &Client{
-		AccessToken: DoLogin(authLoginRequest),
-		Graphql:     graphql.NewClient(credentials.Endpoint),
-	}, nil
+	if client.accessToken == rejected {
+		client.accessToken = ""
+	}
 }
 
+// GetCredentials merges the credentials configured in the provider block with
+// those from environment variables and the credentials file. Each value is
+// taken from the first source that sets it, in that order.
 func GetCredentials(config ClientConfig) (ClientCredentials, error) {
-	credentials := config.Credentials
+	var fileCredentials ClientCredentials
+	if config.CredentialsFile != "" {
+		var err error
+		fileCredentials, err = ReadCredentialsFile(config.CredentialsFile, config.Profile)
+		if err != nil {
+			return ClientCredentials{}, err
+		}
+	}
+
+	envCredentials := ClientCredentials{
+		ClientID:     os.Getenv("WIZ_CLIENT_ID"),
+		ClientSecret: os.Getenv("WIZ_CLIENT_SECRET"),
+		Endpoint:     os.Getenv("WIZ_URL"),
+		AuthURL:      os.Getenv("WIZ_AUTH_URL"),
+	}
+
+	credentials := mergeCredentials(config.Credentials, envCredentials, fileCredentials)
+
+	if credentials.ClientID == "" || credentials.ClientSecret == "" {
+		return credentials, errors.New("both client_id and client_secret are needed, set them in the provider block, through WIZ_CLIENT_ID and WIZ_CLIENT_SECRET, or in a credentials file")
+	}
+
+	if credentials.Endpoint == "" && credentials.DataCenter != "" {
+		credentials.Endpoint = endpointFromDataCenter(credentials.DataCenter, credentials.Environment)
+	}
+	if credentials.Endpoint == "" {
+		return credentials, errors.New("no Wiz API endpoint configured, set endpoint or data_center in the provider block, or WIZ_URL")
+	}
+	if u, err := url.Parse(credentials.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		return credentials, fmt.Errorf("invalid endpoint %q, expected a URL such as https://api.eu1.app.wiz.io/graphql", credentials.Endpoint)
+	}
+
+	environment := credentials.Environment
+	if environment == "" {
+		environment = environmentFromEndpoint(credentials.Endpoint)
+	}
+
+	settings, ok := AuthEnvironments[environment]
+	if !ok {
+		return credentials, fmt.Errorf("unknown environment %q", environment)
+	}
+
+	if credentials.AuthURL == "" {
+		credentials.AuthURL = settings.URL
+	}
+	if credentials.AuthAudience == "" {
+		credentials.AuthAudience = settings.Audience
+	}
+
+	if _, err := url.ParseRequestURI(credentials.AuthURL); err != nil {
+		return credentials, fmt.Errorf("invalid auth_url %q: %s", credentials.AuthURL, err.Error())
+	}
 
 	return credentials, nil
 }
 
-func (client *Client) doRequest(query string, vars map[string]interface{}, responseData interface{}) error {
-	req := graphql.NewRequest(query)
+func mergeCredentials(sources ...ClientCredentials) ClientCredentials {
+	var merged ClientCredentials
+	for i := len(sources) - 1; i >= 0; i-- {
+		source := sources[i]
+		overrideIfSet(&merged.ClientID, source.ClientID)
+		overrideIfSet(&merged.ClientSecret, source.ClientSecret)
+		overrideIfSet(&merged.Endpoint, source.Endpoint)
+		overrideIfSet(&merged.AuthURL, source.AuthURL)
+		overrideIfSet(&merged.AuthAudience, source.AuthAudience)
+		overrideIfSet(&merged.Environment, source.Environment)
+		overrideIfSet(&merged.DataCenter, source.DataCenter)
+	}
+	return merged
+}
+
+func overrideIfSet(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
 
-	if vars != nil {
-		for k, v := range vars {
-			req.Var(k, v)
+// endpointFromDataCenter builds the API endpoint of a data center, e.g. us17
+// becomes https://api.us17.app.wiz.io/graphql.
+func endpointFromDataCenter(dataCenter string, environment string) string {
+	domain := "app.wiz.io"
+	if environment == "gov" || environment == "fedramp" {
+		domain = "gov.wiz.io"
+	}
+	return fmt.Sprintf("https://api.%s.%s/graphql", dataCenter, domain)
+}
+
+// environmentFromEndpoint guesses the environment of a tenant from the host
+// of its API endpoint. Only the domain tells commercial and government
+// tenants apart, so both default to their Auth0 environment, which is the auth
+// server the provider has always used: https://api.us17.gov.wiz.io/graphql is
+// "fedramp" and any other endpoint is "auth0". Tenants on the newer auth
+// servers set environment to "commercial" or "gov".
+func environmentFromEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err == nil && strings.HasSuffix(u.Hostname(), ".gov.wiz.io") {
+		return "fedramp"
+	}
+	return "auth0"
+}
+
+func (client *Client) doRequest(ctx context.Context, query string, vars map[string]interface{}, responseData interface{}) error {
+	if client.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.requestTimeout)
+		defer cancel()
+	}
+
+	accessToken, err := client.token(ctx)
+	if err != nil {
+		return err
+	}
+	err = client.runRequest(ctx, query, vars, responseData, accessToken)
+
+	// the token was revoked or expired early, log in again and retry once
+	if err != nil && errorsHandler.UnauthorizedError(err) {
+		client.invalidateToken(accessToken)
+		if accessToken, err = client.token(ctx); err != nil {
+			return err
 		}
+		err = client.runRequest(ctx, query, vars, responseData, accessToken)
 	}
 
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Authorization", "Bearer "+client.AccessToken)
+	return err
+}
 
-	// define a Context for the request
-	ctx := context.Background()
+func (client *Client) runRequest(ctx context.Context, query string, vars map[string]interface{}, responseData interface{}, accessToken string) error {
+	header := http.Header{}
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Authorization", "Bearer "+accessToken)
 
 	// run and capture the response
-	if err := client.Graphql.Run(ctx, req, &responseData); err != nil {
-		err = errorsHandler.BuildErrorMessage(err)
+	resp, err := client.Graphql.Do(ctx, GraphQLRequest{Query: query, Variables: vars}, header)
+	if err != nil {
 		return err
 	}
-	return nil
+
+	// decode partial data as well, the error below is still returned
+	if len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, responseData); err != nil {
+			return fmt.Errorf("failed to decode GraphQL response data: %w", err)
+		}
+	}
+
+	return resp.Err()
 }
 
 func (client *Client) handleCreateError(err error, input map[string]interface{}, resourceType string) error {
 	parent := input["parent"]
 	if errorsHandler.NotFoundError(err) {
-		return errors.New(fmt.Sprintf("error creating %s: parent resource not found: %s", resourceType, parent))
+		return fmt.Errorf("error creating %s: parent resource not found: %s: %w", resourceType, parent, err)
 	}
-	return errors.New(fmt.Sprintf("error creating %s: %s ", resourceType, err.Error()))
+	return fmt.Errorf("error creating %s: %w", resourceType, err)
 }
 
 func (client *Client) handleReadError(err error, resource string, resourceType string) error {
 	if errorsHandler.NotFoundError(err) {
-		return errors.New(fmt.Sprintf("error reading %s: resource not found: %s", resourceType, resource))
+		return fmt.Errorf("error reading %s: resource not found: %s: %w", resourceType, resource, err)
 	}
-	return errors.New(fmt.Sprintf("error reading %s: %s ", resourceType, err.Error()))
+	return fmt.Errorf("error reading %s: %w", resourceType, err)
 }
 
 func (client *Client) handleUpdateError(err error, input map[string]interface{}, resourceType string) error {
 	resource := input["id"]
 	if errorsHandler.NotFoundError(err) {
-		return errors.New(fmt.Sprintf("error updating %s: resource not found: %s", resourceType, resource))
+		return fmt.Errorf("error updating %s: resource not found: %s: %w", resourceType, resource, err)
+	}
+	return fmt.Errorf("error updating %s: %w", resourceType, err)
+}
+
+func (client *Client) handleDeleteError(err error, input map[string]interface{}, resourceType string) error {
+	resource := input["id"]
+	if errorsHandler.NotFoundError(err) {
+		return fmt.Errorf("error deleting %s: resource not found: %s: %w", resourceType, resource, err)
 	}
-	return errors.New(fmt.Sprintf("error updating %s: %s ", resourceType, err.Error()))
+	return fmt.Errorf("error deleting %s: %w", resourceType, err)
+}
+
+// inputOf returns the "input" variable of a mapped mutation request, which is
+// what the error handlers expect to inspect.
+func inputOf(request map[string]interface{}) map[string]interface{} {
+	input, _ := request["input"].(map[string]interface{})
+	return input
 }
@@ -1,16 +1,49 @@
 package apiClient
 
+import (
+	"net/http"
+	"time"
+)
+
 type ClientConfig struct {
-	Credentials ClientCredentials
+	Credentials     ClientCredentials
+	CredentialsFile string
+	Profile         string
+	MaxRetries      int
+	RetryMaxWait    time.Duration
+	RequestTimeout  time.Duration
+	// Transport sends the API requests, http.DefaultTransport when nil.
+	// Retries are layered on top of it.
+	Transport http.RoundTripper
 }
 
 type ClientCredentials struct {
 	ClientID     string
 	ClientSecret string
 	Endpoint     string
+	AuthURL      string
+	AuthAudience string
+	Environment  string
+	DataCenter   string
+}
+
+type AuthSettings struct {
+	URL      string
+	Audience string
+}
+
+// AuthEnvironments maps the supported Wiz environments onto the auth server
+// and audience used to log in to them.
+var AuthEnvironments = map[string]AuthSettings{
+	"commercial": {URL: "https://auth.app.wiz.io/oauth/token", Audience: "wiz-api"},
+	"auth0":      {URL: "https://auth.wiz.io/oauth/token", Audience: "beyond-api"},
+	"gov":        {URL: "https://auth.gov.wiz.io/oauth/token", Audience: "wiz-api"},
+	"fedramp":    {URL: "https://auth0.gov.wiz.io/oauth/token", Audience: "beyond-api"},
 }
 
 type TokenResponse struct {
 	AccessToken string `json:"access_token"`
 	Scope       string `json:"scope"`
+	ExpiresIn   int64  `json:"expires_in"`
+	TokenType   string `json:"token_type"`
 }
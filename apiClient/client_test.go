@@ -0,0 +1,305 @@
+package apiClient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
+)
+
+// fakeWiz serves an auth server handing out numbered access tokens and a
+// GraphQL endpoint accepting the tokens that are not rejected.
+type fakeWiz struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	expiresIn int64
+	logins    int
+	requests  []string
+	rejected  map[string]bool
+}
+
+func newFakeWiz(t *testing.T, expiresIn int64) *fakeWiz {
+	wiz := &fakeWiz{expiresIn: expiresIn, rejected: map[string]bool{}}
+	wiz.Server = httptest.NewServer(http.HandlerFunc(wiz.serveHTTP))
+	t.Cleanup(wiz.Close)
+	return wiz
+}
+
+func (wiz *fakeWiz) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	wiz.mu.Lock()
+	defer wiz.mu.Unlock()
+
+	if r.URL.Path == "/oauth/token" {
+		wiz.logins++
+		json.NewEncoder(w).Encode(TokenResponse{
+			AccessToken: fmt.Sprintf("token-%d", wiz.logins),
+			ExpiresIn:   wiz.expiresIn,
+		})
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	wiz.requests = append(wiz.requests, token)
+	if wiz.rejected[token] {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("Unauthorized"))
+		return
+	}
+	w.Write([]byte(`{"data":{"project":{"id":"p1"}}}`))
+}
+
+// newTestClient returns a client logged in to the fake Wiz.
+func newTestClient(t *testing.T, wiz *fakeWiz) *Client {
+	client, err := CreateClient(context.Background(), ClientConfig{
+		Credentials: ClientCredentials{
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Endpoint:     wiz.URL + "/graphql",
+			AuthURL:      wiz.URL + "/oauth/token",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateClient() error = %s", err)
+	}
+	return client
+}
+
+type projectResponse struct {
+	Project struct {
+		ID string `json:"id"`
+	} `json:"project"`
+}
+
+func TestTokenRefresh(t *testing.T) {
+	tests := []struct {
+		name       string
+		expiresIn  int64
+		expire     bool
+		wantLogins int
+	}{
+		{"token reused while valid", 3600, false, 1},
+		{"token without expiry reused", 0, false, 1},
+		{"token refreshed before it expires", 3600, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiz := newFakeWiz(t, tt.expiresIn)
+			client := newTestClient(t, wiz)
+			if tt.expire {
+				client.tokenRefreshAt = time.Now().Add(-time.Second)
+			}
+
+			token, err := client.token(context.Background())
+			if err != nil {
+				t.Fatalf("token() error = %s", err)
+			}
+			if want := fmt.Sprintf("token-%d", tt.wantLogins); token != want {
+				t.Errorf("token() = %q, want %q", token, want)
+			}
+			if wiz.logins != tt.wantLogins {
+				t.Errorf("logged in %d times, want %d", wiz.logins, tt.wantLogins)
+			}
+		})
+	}
+}
+
+func TestTokenRefreshMargin(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn int64
+		want      time.Duration
+	}{
+		{"refreshed ahead of expiry", 3600, 3600*time.Second - tokenRefreshMargin},
+		{"short lived token refreshed halfway", 60, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			client := newTestClient(t, newFakeWiz(t, tt.expiresIn))
+
+			refreshIn := client.tokenRefreshAt.Sub(start)
+			if refreshIn < tt.want || refreshIn > tt.want+time.Minute {
+				t.Errorf("token refreshed after %s, want %s", refreshIn, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRequestRetriesUnauthorized(t *testing.T) {
+	tests := []struct {
+		name         string
+		rejected     []string
+		wantErr      bool
+		wantRequests []string
+	}{
+		{"accepted token", nil, false, []string{"token-1"}},
+		{"rejected token replaced", []string{"token-1"}, false, []string{"token-1", "token-2"}},
+		{"retried only once", []string{"token-1", "token-2"}, true, []string{"token-1", "token-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiz := newFakeWiz(t, 3600)
+			for _, token := range tt.rejected {
+				wiz.rejected[token] = true
+			}
+			client := newTestClient(t, wiz)
+
+			response := &projectResponse{}
+			err := client.doRequest(context.Background(), `query { project { id } }`, nil, response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("doRequest() error = %v, want error %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && response.Project.ID != "p1" {
+				t.Errorf("doRequest() decoded project %q, want p1", response.Project.ID)
+			}
+			if strings.Join(wiz.requests, ",") != strings.Join(tt.wantRequests, ",") {
+				t.Errorf("requests sent with tokens %v, want %v", wiz.requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDoLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantKind error
+	}{
+		{"access token", http.StatusOK, `{"access_token":"token","expires_in":3600}`, nil},
+		{"rejected client", http.StatusUnauthorized, `{"error":"access_denied","error_description":"Unauthorized"}`, errorsHandler.ErrInvalidCredentials},
+		{"invalid client", http.StatusBadRequest, `{"error":"invalid_client"}`, errorsHandler.ErrInvalidCredentials},
+		{"unknown audience", http.StatusBadRequest, `{"error":"invalid_request","error_description":"Service not found"}`, errorsHandler.ErrUnexpectedAuthResponse},
+		{"server error", http.StatusInternalServerError, `<html>oops</html>`, errorsHandler.ErrUnexpectedAuthResponse},
+		{"not json", http.StatusOK, `<html>maintenance</html>`, errorsHandler.ErrUnexpectedAuthResponse},
+		{"no access token", http.StatusOK, `{}`, errorsHandler.ErrUnexpectedAuthResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := DoLogin(AuthLoginRequest(context.Background(), ClientCredentials{AuthURL: server.URL}))
+			if tt.wantKind == nil {
+				if err != nil {
+					t.Fatalf("DoLogin() error = %s", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantKind) {
+				t.Errorf("DoLogin() error = %v, want %v", err, tt.wantKind)
+			}
+			var authErr *errorsHandler.AuthError
+			if !errors.As(err, &authErr) || authErr.URL != server.URL || authErr.StatusCode != tt.status {
+				t.Errorf("DoLogin() error = %#v, want an AuthError for %s with status %d", err, server.URL, tt.status)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := DoLogin(AuthLoginRequest(context.Background(), ClientCredentials{AuthURL: server.URL}))
+		if !errors.Is(err, errorsHandler.ErrAuthServerUnreachable) {
+			t.Errorf("DoLogin() error = %v, want %v", err, errorsHandler.ErrAuthServerUnreachable)
+		}
+	})
+}
+
+func TestGetCredentials(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials.yaml")
+	os.WriteFile(file, []byte(`
+default:
+  client_id: file-id
+  client_secret: file-secret
+  url: https://api.file.app.wiz.io/graphql
+  auth_url: https://auth.file.wiz.io/oauth/token
+other:
+  client_id: other-id
+  client_secret: other-secret
+  url: https://api.other.app.wiz.io/graphql
+  auth_url: https://auth.other.wiz.io/oauth/token
+`), 0600)
+
+	provider := ClientCredentials{ClientID: "hcl-id", ClientSecret: "hcl-secret"}
+
+	tests := []struct {
+		name    string
+		config  ClientConfig
+		env     map[string]string
+		want    ClientCredentials
+		wantErr bool
+	}{
+		{
+			name:   "credentials file",
+			config: ClientConfig{CredentialsFile: file},
+			want:   ClientCredentials{ClientID: "file-id", ClientSecret: "file-secret", Endpoint: "https://api.file.app.wiz.io/graphql", AuthURL: "https://auth.file.wiz.io/oauth/token"},
+		},
+		{
+			name:   "credentials file profile",
+			config: ClientConfig{CredentialsFile: file, Profile: "other"},
+			want:   ClientCredentials{ClientID: "other-id", ClientSecret: "other-secret", Endpoint: "https://api.other.app.wiz.io/graphql", AuthURL: "https://auth.other.wiz.io/oauth/token"},
+		},
+		{
+			name:   "environment over credentials file",
+			config: ClientConfig{CredentialsFile: file},
+			env:    map[string]string{"WIZ_CLIENT_ID": "env-id", "WIZ_CLIENT_SECRET": "env-secret", "WIZ_URL": "https://api.env.app.wiz.io/graphql"},
+			want:   ClientCredentials{ClientID: "env-id", ClientSecret: "env-secret", Endpoint: "https://api.env.app.wiz.io/graphql", AuthURL: "https://auth.file.wiz.io/oauth/token"},
+		},
+		{
+			name:   "provider block over environment",
+			config: ClientConfig{Credentials: provider, CredentialsFile: file},
+			env:    map[string]string{"WIZ_CLIENT_ID": "env-id", "WIZ_AUTH_URL": "https://auth.env.wiz.io/oauth/token"},
+			want:   ClientCredentials{ClientID: "hcl-id", ClientSecret: "hcl-secret", Endpoint: "https://api.file.app.wiz.io/graphql", AuthURL: "https://auth.env.wiz.io/oauth/token"},
+		},
+		{
+			name:    "missing client secret",
+			config:  ClientConfig{Credentials: ClientCredentials{ClientID: "hcl-id", Endpoint: "https://api.eu1.app.wiz.io/graphql"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown profile",
+			config:  ClientConfig{CredentialsFile: file, Profile: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "missing credentials file",
+			config:  ClientConfig{CredentialsFile: filepath.Join(t.TempDir(), "missing.yaml")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"WIZ_CLIENT_ID", "WIZ_CLIENT_SECRET", "WIZ_URL", "WIZ_AUTH_URL"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			got, err := GetCredentials(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCredentials() error = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.ClientID != tt.want.ClientID || got.ClientSecret != tt.want.ClientSecret ||
+				got.Endpoint != tt.want.Endpoint || got.AuthURL != tt.want.AuthURL {
+				t.Errorf("GetCredentials() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
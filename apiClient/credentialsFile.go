@@ -0,0 +1,67 @@
+package apiClient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultProfile = "default"
+
+// CredentialsProfile is a named set of credentials in a credentials file.
+// The file is a YAML (or JSON) document keyed by profile name:
+//
+//	default:
+//	  client_id: ...
+//	  client_secret: ...
+//	  url: https://api.eu1.app.wiz.io/graphql
+type CredentialsProfile struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	URL          string `yaml:"url"`
+	AuthURL      string `yaml:"auth_url"`
+	AuthAudience string `yaml:"auth_audience"`
+	Environment  string `yaml:"environment"`
+}
+
+func ReadCredentialsFile(path string, profile string) (ClientCredentials, error) {
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ClientCredentials{}, fmt.Errorf("failed to expand credentials file path %s: %s", path, err.Error())
+		}
+		path = filepath.Join(home, path[2:])
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ClientCredentials{}, fmt.Errorf("failed to read credentials file: %s", err.Error())
+	}
+
+	// YAML is a superset of JSON, so this handles both formats
+	var profiles map[string]CredentialsProfile
+	if err := yaml.Unmarshal(content, &profiles); err != nil {
+		return ClientCredentials{}, fmt.Errorf("failed to parse credentials file %s: %s", path, err.Error())
+	}
+
+	p, ok := profiles[profile]
+	if !ok {
+		return ClientCredentials{}, fmt.Errorf("profile %q not found in credentials file %s", profile, path)
+	}
+
+	return ClientCredentials{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		Endpoint:     p.URL,
+		AuthURL:      p.AuthURL,
+		AuthAudience: p.AuthAudience,
+		Environment:  p.Environment,
+	}, nil
+}
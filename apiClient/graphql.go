@@ -0,0 +1,159 @@
+package apiClient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
+)
+
+// GraphQLClient sends GraphQL requests to the Wiz API. Unlike generic GraphQL
+// clients it keeps the HTTP status, the response headers and every GraphQL
+// error next to the data, so failures can be classified precisely.
+type GraphQLClient struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+type GraphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type GraphQLResponse struct {
+	StatusCode int
+	// Header holds the response headers, e.g. the request ID and rate limit
+	// headers.
+	Header http.Header
+	Data   json.RawMessage
+	// Errors is the "errors" list of the response. Data may still hold a
+	// partial result when it is not empty.
+	Errors []errorsHandler.GraphQLError
+}
+
+// NewGraphQLClient returns a client for endpoint sending its requests through
+// transport, or through http.DefaultTransport when transport is nil.
+func NewGraphQLClient(endpoint string, transport http.RoundTripper) *GraphQLClient {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &GraphQLClient{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Transport: transport},
+	}
+}
+
+// Do sends a request and decodes the response. An error is only returned
+// when no GraphQL response was received, or a successful one could not be
+// decoded; GraphQL errors are reported in the response instead.
+func (c *GraphQLClient) Do(ctx context.Context, request GraphQLRequest, header http.Header) (*GraphQLResponse, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GraphQL request: %w", err)
+	}
+
+	if isMutation(request.Query) {
+		ctx = withMutation(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GraphQL response: %w", err)
+	}
+
+	response := &GraphQLResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+
+	var envelope struct {
+		Data   json.RawMessage              `json:"data"`
+		Errors []errorsHandler.GraphQLError `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		// error responses of proxies and load balancers are often not JSON,
+		// their status code is all there is to go on
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return response, nil
+		}
+		return nil, fmt.Errorf("failed to decode GraphQL response (HTTP %d): %w: %s",
+			resp.StatusCode, err, truncateBody(body))
+	}
+	response.Data = envelope.Data
+	response.Errors = envelope.Errors
+
+	return response, nil
+}
+
+// maxBodyExcerpt is the number of bytes of an undecodable response body that
+// are quoted in the error.
+const maxBodyExcerpt = 256
+
+// truncateBody returns the start of body for use in an error message.
+func truncateBody(body []byte) string {
+	if len(body) <= maxBodyExcerpt {
+		return string(body)
+	}
+	return string(body[:maxBodyExcerpt]) + "..."
+}
+
+// isMutation tells whether query is a GraphQL mutation rather than a query,
+// skipping the whitespace and comments it may start with.
+func isMutation(query string) bool {
+	for {
+		query = strings.TrimSpace(query)
+		if !strings.HasPrefix(query, "#") {
+			break
+		}
+		end := strings.IndexByte(query, '\n')
+		if end < 0 {
+			return false
+		}
+		query = query[end:]
+	}
+	return strings.HasPrefix(query, "mutation")
+}
+
+// Err returns the typed error of the response, or nil when the request
+// succeeded.
+func (r *GraphQLResponse) Err() error {
+	return errorsHandler.NewAPIError(r.StatusCode, r.Header, r.Errors)
+}
+
+// readBody reads the response body, decompressing it when it was gzipped. As
+// Accept-Encoding is set explicitly, net/http leaves that to us.
+func readBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	return io.ReadAll(reader)
+}
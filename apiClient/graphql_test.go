@@ -0,0 +1,156 @@
+package apiClient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipped(body string) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(body))
+	gz.Close()
+	return buf.String()
+}
+
+func TestGraphQLClientDo(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		encoding   string
+		body       string
+		wantData   string
+		wantErrors int
+		wantErr    string
+	}{
+		{
+			name:     "data",
+			status:   http.StatusOK,
+			body:     `{"data":{"project":{"id":"p1"}}}`,
+			wantData: `{"project":{"id":"p1"}}`,
+			wantErr:  "<nil>",
+		},
+		{
+			name:     "gzipped data",
+			status:   http.StatusOK,
+			encoding: "gzip",
+			body:     gzipped(`{"data":{"project":{"id":"p1"}}}`),
+			wantData: `{"project":{"id":"p1"}}`,
+			wantErr:  "<nil>",
+		},
+		{
+			name:       "partial data with errors",
+			status:     http.StatusOK,
+			body:       `{"data":{"project":{"id":"p1"},"owner":null},"errors":[{"message":"Resource not found","path":["owner"],"extensions":{"code":"NOT_FOUND"}},{"message":"denied"}]}`,
+			wantData:   `{"project":{"id":"p1"},"owner":null}`,
+			wantErrors: 2,
+			wantErr:    "*errors.NotFound",
+		},
+		{
+			name:       "errors without data",
+			status:     http.StatusBadRequest,
+			body:       `{"errors":[{"message":"Unknown argument","extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}]}`,
+			wantErrors: 1,
+			wantErr:    "*errors.Validation",
+		},
+		{
+			name:    "error status without json",
+			status:  http.StatusBadGateway,
+			body:    `<html>Bad Gateway</html>`,
+			wantErr: "*errors.ServerError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Header().Set("X-Request-Id", "req-1")
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			resp, err := NewGraphQLClient(server.URL, nil).Do(context.Background(), GraphQLRequest{Query: "query { project { id } }"}, nil)
+			if err != nil {
+				t.Fatalf("Do() error = %s", err)
+			}
+			if resp.StatusCode != tt.status || resp.Header.Get("X-Request-Id") != "req-1" {
+				t.Errorf("Do() = status %d and request ID %q, want %d and req-1", resp.StatusCode, resp.Header.Get("X-Request-Id"), tt.status)
+			}
+			if string(resp.Data) != tt.wantData {
+				t.Errorf("Do() data = %s, want %s", resp.Data, tt.wantData)
+			}
+			if len(resp.Errors) != tt.wantErrors {
+				t.Errorf("Do() returned %d GraphQL errors, want %d", len(resp.Errors), tt.wantErrors)
+			}
+			if got := fmt.Sprintf("%T", resp.Err()); got != tt.wantErr {
+				t.Errorf("Err() = %s, want %s", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraphQLClientDoUndecodable(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"html", `<html>maintenance</html>`, "failed to decode GraphQL response (HTTP 200): invalid character '<' looking for beginning of value: <html>maintenance</html>"},
+		{"truncated json", `{"data":{"project":`, "failed to decode GraphQL response (HTTP 200): unexpected end of JSON input: {\"data\":{\"project\":"},
+		{"long body", strings.Repeat("x", 300), "failed to decode GraphQL response (HTTP 200): invalid character 'x' looking for beginning of value: " + strings.Repeat("x", maxBodyExcerpt) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			resp, err := NewGraphQLClient(server.URL, nil).Do(context.Background(), GraphQLRequest{Query: "query { project { id } }"}, nil)
+			if resp != nil || err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Do() = %v, %v, want error %q", resp, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraphQLClientDoRequest(t *testing.T) {
+	var got struct {
+		header  http.Header
+		request GraphQLRequest
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.header = r.Header
+		json.NewDecoder(r.Body).Decode(&got.request)
+		io.WriteString(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	_, err := NewGraphQLClient(server.URL, nil).Do(context.Background(), GraphQLRequest{
+		Query:     "query Project($id: ID!) { project(id: $id) { id } }",
+		Variables: map[string]interface{}{"id": "p1"},
+	}, header)
+	if err != nil {
+		t.Fatalf("Do() error = %s", err)
+	}
+
+	if got.header.Get("Authorization") != "Bearer token" || got.header.Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("request headers = %v, want the given authorization and a JSON content type", got.header)
+	}
+	if got.request.Query != "query Project($id: ID!) { project(id: $id) { id } }" || got.request.Variables["id"] != "p1" {
+		t.Errorf("request = %+v, want the query and its variables", got.request)
+	}
+}
@@ -0,0 +1,76 @@
+package apiClient
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	DefaultPageSize   = 100
+	DefaultMaxResults = 10000
+)
+
+// ErrMaxResultsReached is returned by Paginate, together with the first
+// MaxResults nodes, when the connection holds more nodes than that.
+var ErrMaxResultsReached = errors.New("maximum number of results reached")
+
+// Connection is a page of a cursor paginated GraphQL connection.
+type Connection[T any] struct {
+	PageInfo PageInfo `json:"pageInfo"`
+	Nodes    []T      `json:"nodes"`
+}
+
+type PaginateOptions struct {
+	// PageSize is the number of nodes requested per page, DefaultPageSize
+	// when zero.
+	PageSize int
+	// MaxResults bounds the number of nodes fetched in total,
+	// DefaultMaxResults when zero.
+	MaxResults int
+}
+
+// PageFetcher fetches the page of first nodes following the after cursor, or
+// the first page when after is nil.
+type PageFetcher[T any] func(ctx context.Context, first int, after *string) (*Connection[T], error)
+
+// Paginate follows the cursors of a connection and returns all of its nodes.
+func Paginate[T any](ctx context.Context, fetch PageFetcher[T], opts PaginateOptions) ([]T, error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = DefaultMaxResults
+	}
+
+	var nodes []T
+	var after *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nodes, err
+		}
+
+		first := pageSize
+		if remaining := maxResults - len(nodes) + 1; remaining < first {
+			// fetch one node more than allowed to tell whether there are more
+			first = remaining
+		}
+
+		page, err := fetch(ctx, first, after)
+		if err != nil {
+			return nodes, err
+		}
+		nodes = append(nodes, page.Nodes...)
+
+		if len(nodes) > maxResults {
+			return nodes[:maxResults], ErrMaxResultsReached
+		}
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" || len(page.Nodes) == 0 {
+			return nodes, nil
+		}
+
+		cursor := page.PageInfo.EndCursor
+		after = &cursor
+	}
+}
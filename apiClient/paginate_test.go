@@ -0,0 +1,126 @@
+package apiClient
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// pagedNodes serves total nodes numbered from 0, in pages of at most first
+// nodes, using the index of the next node as cursor.
+func pagedNodes(total int, requests *[]int) PageFetcher[int] {
+	return func(ctx context.Context, first int, after *string) (*Connection[int], error) {
+		*requests = append(*requests, first)
+
+		start := 0
+		if after != nil {
+			start, _ = strconv.Atoi(*after)
+		}
+		end := start + first
+		if end > total {
+			end = total
+		}
+
+		page := &Connection[int]{}
+		for i := start; i < end; i++ {
+			page.Nodes = append(page.Nodes, i)
+		}
+		page.PageInfo.HasNextPage = end < total
+		page.PageInfo.EndCursor = strconv.Itoa(end)
+		return page, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int
+		opts         PaginateOptions
+		wantNodes    int
+		wantErr      error
+		wantRequests []int
+	}{
+		{"empty", 0, PaginateOptions{PageSize: 10}, 0, nil, []int{10}},
+		{"single page", 5, PaginateOptions{PageSize: 10}, 5, nil, []int{10}},
+		{"exact pages", 20, PaginateOptions{PageSize: 10}, 20, nil, []int{10, 10}},
+		{"several pages", 25, PaginateOptions{PageSize: 10}, 25, nil, []int{10, 10, 10}},
+		{"default page size", 150, PaginateOptions{}, 150, nil, []int{DefaultPageSize, DefaultPageSize}},
+		{"exactly max results", 3, PaginateOptions{PageSize: 10, MaxResults: 3}, 3, nil, []int{4}},
+		{"over max results", 4, PaginateOptions{PageSize: 10, MaxResults: 3}, 3, ErrMaxResultsReached, []int{4}},
+		{"over max results across pages", 30, PaginateOptions{PageSize: 10, MaxResults: 15}, 15, ErrMaxResultsReached, []int{10, 6}},
+		{"single result lookup", 1, PaginateOptions{MaxResults: 1}, 1, nil, []int{2}},
+		{"ambiguous single result lookup", 2, PaginateOptions{MaxResults: 1}, 1, ErrMaxResultsReached, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []int
+			nodes, err := Paginate(context.Background(), pagedNodes(tt.total, &requests), tt.opts)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Paginate() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(nodes) != tt.wantNodes {
+				t.Errorf("Paginate() returned %d nodes, want %d", len(nodes), tt.wantNodes)
+			}
+			for i, node := range nodes {
+				if node != i {
+					t.Fatalf("Paginate() node %d = %d, want the nodes in order", i, node)
+				}
+			}
+			if len(requests) != len(tt.wantRequests) {
+				t.Fatalf("Paginate() requested pages of %v, want %v", requests, tt.wantRequests)
+			}
+			for i := range requests {
+				if requests[i] != tt.wantRequests[i] {
+					t.Fatalf("Paginate() requested pages of %v, want %v", requests, tt.wantRequests)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginateStopsOnBrokenCursor(t *testing.T) {
+	tests := []struct {
+		name string
+		page Connection[int]
+	}{
+		{"empty cursor", Connection[int]{PageInfo: PageInfo{HasNextPage: true}, Nodes: []int{1}}},
+		{"empty page", Connection[int]{PageInfo: PageInfo{HasNextPage: true, EndCursor: "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			_, err := Paginate(context.Background(), func(ctx context.Context, first int, after *string) (*Connection[int], error) {
+				calls++
+				page := tt.page
+				return &page, nil
+			}, PaginateOptions{})
+			if err != nil || calls != 1 {
+				t.Errorf("Paginate() = %v after %d requests, want no error after 1", err, calls)
+			}
+		})
+	}
+}
+
+func TestPaginateErrors(t *testing.T) {
+	fetchErr := errors.New("boom")
+	calls := 0
+	nodes, err := Paginate(context.Background(), func(ctx context.Context, first int, after *string) (*Connection[int], error) {
+		calls++
+		if calls == 2 {
+			return nil, fetchErr
+		}
+		cursor := "1"
+		return &Connection[int]{PageInfo: PageInfo{HasNextPage: true, EndCursor: cursor}, Nodes: []int{0}}, nil
+	}, PaginateOptions{})
+	if !errors.Is(err, fetchErr) || len(nodes) != 1 {
+		t.Errorf("Paginate() = %v, %v, want the first page and the fetch error", nodes, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Paginate(ctx, pagedNodes(10, new([]int)), PaginateOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Paginate() with a cancelled context error = %v, want %v", err, context.Canceled)
+	}
+}
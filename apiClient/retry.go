@@ -0,0 +1,143 @@
+package apiClient
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries   = 5
+	DefaultRetryMaxWait = 30 * time.Second
+
+	retryBaseWait = 1 * time.Second
+)
+
+// retryTransport is an http.RoundTripper that retries requests failing with a
+// transient status code, as told by isRetryable, using jittered exponential
+// backoff. A Retry-After header sent by the server is honored, and the wait is
+// cut short when the request context is cancelled.
+type retryTransport struct {
+	next         http.RoundTripper
+	maxRetries   int
+	retryMaxWait time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, maxRetries int, retryMaxWait time.Duration) *retryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if maxRetries < 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	if retryMaxWait <= 0 {
+		retryMaxWait = DefaultRetryMaxWait
+	}
+	return &retryTransport{
+		next:         next,
+		maxRetries:   maxRetries,
+		retryMaxWait: retryMaxWait,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(attemptReq)
+		if err != nil || !isRetryable(req, resp.StatusCode) || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		// the body has to be replayed for the next attempt, on a copy of the
+		// request as a RoundTripper must not modify the one it was given
+		attemptReq = req.Clone(req.Context())
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			attemptReq.Body = body
+		}
+
+		wait := t.backoff(attempt, resp.Header.Get("Retry-After"))
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns how long to wait before the given retry attempt, preferring
+// the server's Retry-After value when it sent one.
+func (t *retryTransport) backoff(attempt int, retryAfter string) time.Duration {
+	if wait, ok := parseRetryAfter(retryAfter); ok {
+		if wait > t.retryMaxWait {
+			return t.retryMaxWait
+		}
+		return wait
+	}
+
+	wait := retryBaseWait << uint(attempt)
+	if wait <= 0 || wait > t.retryMaxWait {
+		wait = t.retryMaxWait
+	}
+	// jitter between half and the full wait, so concurrent requests spread out
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isRetryable tells whether a request that failed with the given status code
+// can be sent again. A 429 or 503 means the request was not processed, so it is
+// always retried. A 502 or 504 is only retried for requests other than
+// mutations, as the mutation may well have been applied upstream.
+func isRetryable(req *http.Request, code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return !isMutationRequest(req.Context())
+	}
+	return false
+}
+
+type mutationKey struct{}
+
+// withMutation marks the requests sent with ctx as GraphQL mutations, which
+// are not safe to retry after a gateway failure.
+func withMutation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, mutationKey{}, true)
+}
+
+func isMutationRequest(ctx context.Context) bool {
+	mutation, _ := ctx.Value(mutationKey{}).(bool)
+	return mutation
+}
@@ -0,0 +1,201 @@
+package apiClient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestIsRetryable(t *testing.T) {
+	query, _ := http.NewRequest(http.MethodPost, "https://api.example.com/graphql", nil)
+	mutation := query.WithContext(withMutation(context.Background()))
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		code int
+		want bool
+	}{
+		{"query 429", query, http.StatusTooManyRequests, true},
+		{"query 502", query, http.StatusBadGateway, true},
+		{"query 503", query, http.StatusServiceUnavailable, true},
+		{"query 504", query, http.StatusGatewayTimeout, true},
+		{"query 500", query, http.StatusInternalServerError, false},
+		{"query 200", query, http.StatusOK, false},
+		{"query 400", query, http.StatusBadRequest, false},
+		{"mutation 429", mutation, http.StatusTooManyRequests, true},
+		{"mutation 502", mutation, http.StatusBadGateway, false},
+		{"mutation 503", mutation, http.StatusServiceUnavailable, true},
+		{"mutation 504", mutation, http.StatusGatewayTimeout, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.req, tt.code); got != tt.want {
+				t.Errorf("isRetryable(%d) = %t, want %t", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMutation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"mutation", "mutation CreateProject($input: CreateProjectInput!) {}", true},
+		{"indented mutation", "\n\t  mutation DeleteProject {}", true},
+		{"commented mutation", "# creates a project\n  mutation CreateProject {}", true},
+		{"query", "query Project($id: ID!) {}", false},
+		{"anonymous query", "{ project(id: 1) { id } }", false},
+		{"comment only", "# mutation", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isMutation(tt.query); got != tt.want {
+				t.Errorf("isMutation(%q) = %t, want %t", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"empty", "", 0, false},
+		{"seconds", "7", 7 * time.Second, true},
+		{"zero", "0", 0, true},
+		{"negative", "-3", 0, false},
+		{"garbage", "soon", 0, false},
+		{"past date", "Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	transport := newRetryTransport(nil, 5, 10*time.Second)
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{"first attempt", 0, "", 500 * time.Millisecond, time.Second},
+		{"third attempt", 2, "", 2 * time.Second, 4 * time.Second},
+		{"capped by max wait", 10, "", 5 * time.Second, 10 * time.Second},
+		{"overflowing shift", 80, "", 5 * time.Second, 10 * time.Second},
+		{"retry after", 0, "3", 3 * time.Second, 3 * time.Second},
+		{"retry after capped by max wait", 0, "120", 10 * time.Second, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got := transport.backoff(tt.attempt, tt.retryAfter)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%d, %q) = %s, want between %s and %s", tt.attempt, tt.retryAfter, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		mutation     bool
+		statuses     []int
+		maxRetries   int
+		wantStatus   int
+		wantAttempts int
+	}{
+		{"success", false, []int{200}, 3, 200, 1},
+		{"retried until success", false, []int{503, 429, 200}, 3, 200, 3},
+		{"retries exhausted", false, []int{503, 503, 503}, 2, 503, 3},
+		{"not retryable", false, []int{500, 200}, 3, 500, 1},
+		{"query gateway timeout", false, []int{504, 200}, 3, 200, 2},
+		{"mutation gateway timeout", true, []int{504, 200}, 3, 504, 1},
+		{"mutation rate limited", true, []int{429, 200}, 3, 200, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			var bodies []string
+			next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(req.Body)
+				bodies = append(bodies, string(body))
+				status := tt.statuses[attempts]
+				attempts++
+				return &http.Response{
+					StatusCode: status,
+					Header:     http.Header{"Retry-After": []string{"0"}},
+					Body:       io.NopCloser(strings.NewReader("")),
+				}, nil
+			})
+
+			ctx := context.Background()
+			if tt.mutation {
+				ctx = withMutation(ctx)
+			}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.example.com/graphql", strings.NewReader("payload"))
+			originalBody := req.Body
+
+			resp, err := newRetryTransport(next, tt.maxRetries, time.Millisecond).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %s", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			for i, body := range bodies {
+				if body != "payload" {
+					t.Errorf("body of attempt %d = %q, want the replayed payload", i, body)
+				}
+			}
+			if req.Body != originalBody {
+				t.Error("RoundTrip() modified the body of the caller's request")
+			}
+		})
+	}
+}
+
+func TestRetryTransportCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		cancel()
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{"Retry-After": []string{"60"}},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.example.com/graphql", nil)
+	_, err := newRetryTransport(next, 3, time.Minute).RoundTrip(req)
+	if err != context.Canceled {
+		t.Errorf("RoundTrip() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -2,8 +2,10 @@ package apiClient
 
 import (
 	"context"
+	"errors"
 
 	"github.com/fatih/structs"
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
 )
 
 // #region Create Request Struct
@@ -12,16 +14,19 @@ type CreateProjectRequest struct {
 }
 
 type CreateProjectInput struct {
-	Name                   *string            `structs:"name"`
-	Identifiers            []interface{}      `structs:"identifiers"`
-	CloudOrganizationLinks []interface{}      `structs:"cloudOrganizationLinks"`
-	CloudAccountLinks      []CloudAccountLink `structs:"cloudAccountLinks"`
-	RepositoryLinks        []interface{}      `structs:"repositoryLinks"`
-	Description            string             `structs:"description"`
-	SecurityChampions      []interface{}      `structs:"securityChampions"`
-	ProjectOwners          []interface{}      `structs:"projectOwners"`
-	BusinessUnit           string             `structs:"businessUnit"`
-	RiskProfile            RiskProfile        `structs:"riskProfile"`
+	Name                   *string                 `structs:"name"`
+	Identifiers            []string                `structs:"identifiers"`
+	IsFolder               bool                    `structs:"isFolder"`
+	ParentProjectID        *string                 `structs:"parentProjectId,omitempty"`
+	CloudOrganizationLinks []CloudOrganizationLink `structs:"cloudOrganizationLinks"`
+	CloudAccountLinks      []CloudAccountLink      `structs:"cloudAccountLinks"`
+	RepositoryLinks        []RepositoryLink        `structs:"repositoryLinks"`
+	KubernetesClusterLinks []KubernetesClusterLink `structs:"kubernetesClusterLinks"`
+	Description            string                  `structs:"description"`
+	SecurityChampions      []string                `structs:"securityChampions"`
+	ProjectOwners          []string                `structs:"projectOwners"`
+	BusinessUnit           string                  `structs:"businessUnit"`
+	RiskProfile            RiskProfile             `structs:"riskProfile"`
 }
 
 //#endregion
@@ -53,38 +58,57 @@ type Input struct {
 }
 
 type Override struct {
-	Name                   *string            `structs:"name"`
-	Identifiers            []interface{}      `structs:"identifiers"`
-	CloudOrganizationLinks []interface{}      `structs:"cloudOrganizationLinks"`
-	CloudAccountLinks      []CloudAccountLink `structs:"cloudAccountLinks"`
-	RepositoryLinks        []interface{}      `structs:"repositoryLinks"`
-	KubernetesClusterLinks []interface{}      `structs:"kubernetesClusterLinks"`
-	Description            string             `structs:"description"`
-	SecurityChampions      []interface{}      `structs:"securityChampions"`
-	ProjectOwners          []interface{}      `structs:"projectOwners"`
-	BusinessUnit           string             `structs:"businessUnit"`
-	RiskProfile            RiskProfile        `structs:"riskProfile"`
+	Name                   *string                 `structs:"name"`
+	Identifiers            []string                `structs:"identifiers"`
+	CloudOrganizationLinks []CloudOrganizationLink `structs:"cloudOrganizationLinks"`
+	CloudAccountLinks      []CloudAccountLink      `structs:"cloudAccountLinks"`
+	RepositoryLinks        []RepositoryLink        `structs:"repositoryLinks"`
+	KubernetesClusterLinks []KubernetesClusterLink `structs:"kubernetesClusterLinks"`
+	Description            string                  `structs:"description"`
+	SecurityChampions      []string                `structs:"securityChampions"`
+	ProjectOwners          []string                `structs:"projectOwners"`
+	BusinessUnit           string                  `structs:"businessUnit"`
+	RiskProfile            RiskProfile             `structs:"riskProfile"`
 }
 
 type CloudAccountLink struct {
 	CloudAccount   string        `structs:"cloudAccount"`
 	Environment    string        `structs:"environment"`
 	Shared         bool          `structs:"shared"`
-	ResourceTags   []interface{} `structs:"resourceTags"`
-	ResourceGroups []interface{} `structs:"resourceGroups"`
+	ResourceTags   []ResourceTag `structs:"resourceTags"`
+	ResourceGroups []string      `structs:"resourceGroups"`
+}
+
+type CloudOrganizationLink struct {
+	CloudOrganization string        `structs:"cloudOrganization"`
+	Environment       string        `structs:"environment"`
+	Shared            bool          `structs:"shared"`
+	ResourceTags      []ResourceTag `structs:"resourceTags"`
+	ResourceGroups    []string      `structs:"resourceGroups"`
+}
+
+type KubernetesClusterLink struct {
+	KubernetesCluster string   `structs:"kubernetesCluster"`
+	Environment       string   `structs:"environment"`
+	Namespaces        []string `structs:"namespaces"`
+	Shared            bool     `structs:"shared"`
+}
+
+type RepositoryLink struct {
+	Repository string `structs:"repository"`
 }
 
 type RiskProfile struct {
-	BusinessImpact      string   `structs:"businessImpact"`
-	HasAuthentication   string   `structs:"hasAuthentication"`
-	HasExposedAPI       string   `structs:"hasExposedAPI"`
-	IsCustomerFacing    string   `structs:"isCustomerFacing"`
-	IsInternetFacing    string   `structs:"isInternetFacing"`
-	IsActivelyDeveloped string   `structs:"isActivelyDeveloped"`
-	IsRegulated         string   `structs:"isRegulated"`
-	SensitiveDataTypes  []string `structs:"sensitiveDataTypes"`
-	StoresData          string   `structs:"storesData"`
-	RegulatoryStandards []string `structs:"regulatoryStandards"`
+	BusinessImpact      string   `structs:"businessImpact" json:"businessImpact"`
+	HasAuthentication   string   `structs:"hasAuthentication" json:"hasAuthentication"`
+	HasExposedAPI       string   `structs:"hasExposedAPI" json:"hasExposedAPI"`
+	IsCustomerFacing    string   `structs:"isCustomerFacing" json:"isCustomerFacing"`
+	IsInternetFacing    string   `structs:"isInternetFacing" json:"isInternetFacing"`
+	IsActivelyDeveloped string   `structs:"isActivelyDeveloped" json:"isActivelyDeveloped"`
+	IsRegulated         string   `structs:"isRegulated" json:"isRegulated"`
+	SensitiveDataTypes  []string `structs:"sensitiveDataTypes" json:"sensitiveDataTypes"`
+	StoresData          string   `structs:"storesData" json:"storesData"`
+	RegulatoryStandards []string `structs:"regulatoryStandards" json:"regulatoryStandards"`
 }
 
 // #endregion
@@ -106,9 +130,123 @@ type CloudAccount struct {
 	ID string `json:"id"`
 }
 
+// #region Delete Request Struct
+type DeleteProjectRequest struct {
+	Input DeleteProjectInput `structs:"input"`
+}
+
+type DeleteProjectInput struct {
+	ID string `structs:"id"`
+}
+
+type ArchiveProjectRequest struct {
+	Input ArchiveProjectInput `structs:"input"`
+}
+
+type ArchiveProjectInput struct {
+	ID    string       `structs:"id"`
+	Patch ArchivePatch `structs:"patch"`
+}
+
+type ArchivePatch struct {
+	Archived bool `structs:"archived"`
+}
+
+// #endregion
+
+// #region Delete Response Struct
+type DeleteProjectResponseData struct {
+	DeleteProject DeleteProject `json:"deleteProject"`
+}
+
+type DeleteProject struct {
+	Stub string `json:"_stub"`
+}
+
+type ArchiveProjectResponseData struct {
+	UpdateProject UpdateProject `json:"updateProject"`
+}
+
+// #endregion
+
+// #region Get Project By ID Response Struct
+type GetProjectByIDResponseData struct {
+	Project *ProjectDetails `json:"project"`
+}
+
+type ProjectDetails struct {
+	ID                      string                         `json:"id"`
+	Name                    string                         `json:"name"`
+	Identifiers             []string                       `json:"identifiers"`
+	Description             string                         `json:"description"`
+	BusinessUnit            string                         `json:"businessUnit"`
+	ProjectOwners           []User                         `json:"projectOwners"`
+	SecurityChampions       []User                         `json:"securityChampions"`
+	CloudOrganizationLinks  []CloudOrganizationLinkDetails `json:"cloudOrganizationLinks"`
+	CloudAccountLinks       []CloudAccountLinkDetails      `json:"cloudAccountLinks"`
+	KubernetesClustersLinks []KubernetesClusterLinkDetails `json:"kubernetesClustersLinks"`
+	RepositoryLinks         []RepositoryLinkDetails        `json:"repositoryLinks"`
+	RiskProfile             RiskProfile                    `json:"riskProfile"`
+	IsFolder                bool                           `json:"isFolder"`
+	ParentProject           *Project                       `json:"parentProject"`
+}
+
+type User struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type ResourceTag struct {
+	Key   string `structs:"key" json:"key"`
+	Value string `structs:"value" json:"value"`
+}
+
+type CloudOrganizationLinkDetails struct {
+	CloudOrganization CloudOrganization `json:"cloudOrganization"`
+	Environment       string            `json:"environment"`
+	ResourceTags      []ResourceTag     `json:"resourceTags"`
+	Shared            bool              `json:"shared"`
+	ResourceGroups    []string          `json:"resourceGroups"`
+}
+
+type CloudOrganization struct {
+	ID string `json:"id"`
+}
+
+type CloudAccountLinkDetails struct {
+	CloudAccount   CloudAccount  `json:"cloudAccount"`
+	Environment    string        `json:"environment"`
+	ResourceTags   []ResourceTag `json:"resourceTags"`
+	Shared         bool          `json:"shared"`
+	ResourceGroups []string      `json:"resourceGroups"`
+}
+
+type KubernetesClusterLinkDetails struct {
+	KubernetesCluster KubernetesCluster `json:"kubernetesCluster"`
+	Environment       string            `json:"environment"`
+	Namespaces        []string          `json:"namespaces"`
+	Shared            bool              `json:"shared"`
+}
+
+type KubernetesCluster struct {
+	ID string `json:"id"`
+}
+
+type RepositoryLinkDetails struct {
+	Repository Repository `json:"repository"`
+}
+
+type Repository struct {
+	ID string `json:"id"`
+}
+
+// #endregion
+
 // #region Get Project Request Struct
 type GetProjectRequest struct {
 	First           int64   `structs:"first"`
+	After           *string `structs:"after"`
 	Query           Query   `structs:"query"`
 	ProjectID       *string `structs:"projectId"`
 	FetchTotalCount bool    `structs:"fetchTotalCount"`
@@ -116,7 +254,8 @@ type GetProjectRequest struct {
 }
 
 type Query struct {
-	Type []string `structs:"type"`
+	Type  []string               `structs:"type"`
+	Where map[string]interface{} `structs:"where,omitempty"`
 }
 
 // #endregion
@@ -182,6 +321,79 @@ type Subscriptions []struct {
 
 //#endregion
 
+// projectFields selects every field of a project, it is shared by the queries
+// and mutations returning a project.
+const projectFields = `
+	id
+	name
+	identifiers
+	description
+	businessUnit
+	projectOwners {
+	id
+	name
+	email
+	}
+	securityChampions {
+	id
+	name
+	email
+	}
+	cloudOrganizationLinks {
+	cloudOrganization {
+		id
+	}
+	environment
+	resourceTags {
+		key
+		value
+	}
+	shared
+	resourceGroups
+	}
+	cloudAccountLinks {
+	cloudAccount {
+		id
+	}
+	environment
+	resourceTags {
+		key
+		value
+	}
+	shared
+	resourceGroups
+	}
+	kubernetesClustersLinks {
+	kubernetesCluster {
+		id
+	}
+	environment
+	namespaces
+	shared
+	}
+	repositoryLinks {
+	repository {
+		id
+	}
+	}
+	isFolder
+	parentProject {
+	id
+	}
+	riskProfile {
+	businessImpact
+	hasAuthentication
+	isInternetFacing
+	hasExposedAPI
+	storesData
+	sensitiveDataTypes
+	regulatoryStandards
+	isCustomerFacing
+	isActivelyDeveloped
+	isRegulated
+	}
+`
+
 func (c *Client) CreateWizProject(ctx context.Context, req CreateProjectRequest) (*CreateProjectResponseData, error) {
 	create_req := `
 	  mutation CreateProject($input: CreateProjectInput!) {
@@ -196,7 +408,9 @@ func (c *Client) CreateWizProject(ctx context.Context, req CreateProjectRequest)
 	request_mapped := s.Map()
 	response := &CreateProjectResponseData{}
 
-	c.doRequest(create_req, request_mapped, response)
+	if err := c.doRequest(ctx, create_req, request_mapped, response); err != nil {
+		return nil, c.handleCreateError(err, inputOf(request_mapped), "project")
+	}
 
 	return response, nil
 }
@@ -206,69 +420,7 @@ func (c *Client) UpdateWizProject(ctx context.Context, req UpdateProjectRequest)
 		mutation UpdateProject($input: UpdateProjectInput!) {
 			updateProject(input: $input) {
 			project {
-				id
-				name
-				identifiers
-				description
-				businessUnit
-				projectOwners {
-				id
-				name
-				email
-				}
-				securityChampions {
-				id
-				name
-				email
-				}
-				cloudOrganizationLinks {
-				cloudOrganization {
-					id
-				}
-				environment
-				resourceTags {
-					key
-					value
-				}
-				shared
-				resourceGroups
-				}
-				cloudAccountLinks {
-				cloudAccount {
-					id
-				}
-				environment
-				resourceTags {
-					key
-					value
-				}
-				shared
-				resourceGroups
-				}
-				kubernetesClustersLinks {
-				kubernetesCluster {
-					id
-				}
-				environment
-				namespaces
-				shared
-				}
-				repositoryLinks {
-				repository {
-					id
-				}
-				}
-				riskProfile {
-				businessImpact
-				hasAuthentication
-				isInternetFacing
-				hasExposedAPI
-				storesData
-				sensitiveDataTypes
-				regulatoryStandards
-				isCustomerFacing
-				isRegulated
-				}
+				` + projectFields + `
 			}
 			}
 		}
@@ -278,7 +430,9 @@ func (c *Client) UpdateWizProject(ctx context.Context, req UpdateProjectRequest)
 	request_mapped := s.Map()
 	response := &UpdateProjectResponseData{}
 
-	c.doRequest(update_req, request_mapped, response)
+	if err := c.doRequest(ctx, update_req, request_mapped, response); err != nil {
+		return nil, c.handleUpdateError(err, inputOf(request_mapped), "project")
+	}
 
 	return response, nil
 }
@@ -323,7 +477,128 @@ func (c *Client) GetWizProject(ctx context.Context, req GetProjectRequest) (*Get
 	s := structs.New(req)
 	request_mapped := s.Map()
 	response := &GetProjectResponseData{}
-	c.doRequest(get_req, request_mapped, response)
+	if err := c.doRequest(ctx, get_req, request_mapped, response); err != nil {
+		var projectID string
+		if req.ProjectID != nil {
+			projectID = *req.ProjectID
+		}
+		return nil, c.handleReadError(err, projectID, "project")
+	}
+
+	return response, nil
+}
+
+func (c *Client) DeleteWizProject(ctx context.Context, req DeleteProjectRequest) (*DeleteProjectResponseData, error) {
+	delete_req := `
+	  mutation DeleteProject($input: DeleteProjectInput!) {
+		  deleteProject(input: $input) {
+			_stub
+		  }
+		}`
+
+	s := structs.New(req)
+	request_mapped := s.Map()
+	response := &DeleteProjectResponseData{}
+
+	if err := c.doRequest(ctx, delete_req, request_mapped, response); err != nil {
+		return nil, c.handleDeleteError(errorsHandler.ProjectNotFound(err), inputOf(request_mapped), "project")
+	}
+
+	return response, nil
+}
+
+func (c *Client) ArchiveWizProject(ctx context.Context, req ArchiveProjectRequest) (*ArchiveProjectResponseData, error) {
+	archive_req := `
+	  mutation ArchiveProject($input: UpdateProjectInput!) {
+		  updateProject(input: $input) {
+			project {
+			  id
+			}
+		  }
+		}`
+
+	s := structs.New(req)
+	request_mapped := s.Map()
+	response := &ArchiveProjectResponseData{}
+
+	if err := c.doRequest(ctx, archive_req, request_mapped, response); err != nil {
+		return nil, c.handleDeleteError(errorsHandler.ProjectNotFound(err), inputOf(request_mapped), "project")
+	}
 
 	return response, nil
 }
+
+func (c *Client) GetProjectByID(ctx context.Context, id string) (*GetProjectByIDResponseData, error) {
+	get_req := `
+	query Project($id: ID!) {
+		project(id: $id) {
+		` + projectFields + `
+		}
+	}
+	  `
+	response := &GetProjectByIDResponseData{}
+	if err := c.doRequest(ctx, get_req, map[string]interface{}{"id": id}, response); err != nil {
+		return nil, c.handleReadError(errorsHandler.ProjectNotFound(err), id, "project")
+	}
+
+	return response, nil
+}
+
+// SearchWizProjects returns the entities of every project matching query,
+// following the graph search cursors.
+func (c *Client) SearchWizProjects(ctx context.Context, query Query, opts PaginateOptions) ([]Entity, error) {
+	return c.searchGraph(ctx, query, opts)
+}
+
+// GetProjectEntity returns the graph entity of the project id, which holds
+// the properties the project query does not return, or nil when the graph
+// has no such project yet.
+func (c *Client) GetProjectEntity(ctx context.Context, id string) (*Entity, error) {
+	entities, err := c.searchGraph(ctx, Query{
+		Type: []string{
+			"PROJECT",
+		},
+		Where: map[string]interface{}{
+			"id": map[string]interface{}{
+				"EQUALS": []string{id},
+			},
+		},
+	}, PaginateOptions{MaxResults: 1})
+
+	if err != nil && !errors.Is(err, ErrMaxResultsReached) {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+	return &entities[0], nil
+}
+
+// searchGraph returns every entity matching query across all projects,
+// following the graph search cursors.
+func (c *Client) searchGraph(ctx context.Context, query Query, opts PaginateOptions) ([]Entity, error) {
+	allProjects := "*"
+	nodes, err := Paginate(ctx, func(ctx context.Context, first int, after *string) (*Connection[Node], error) {
+		response, err := c.GetWizProject(ctx, GetProjectRequest{
+			First:           int64(first),
+			After:           after,
+			Query:           query,
+			ProjectID:       &allProjects,
+			FetchTotalCount: false,
+			Quick:           true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &Connection[Node]{
+			PageInfo: response.GraphSearch.PageInfo,
+			Nodes:    response.GraphSearch.Nodes,
+		}, nil
+	}, opts)
+
+	var entities []Entity
+	for _, node := range nodes {
+		entities = append(entities, node.Entities...)
+	}
+	return entities, err
+}
@@ -0,0 +1,95 @@
+package apiClient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
+)
+
+// newGraphQLTestClient returns a client logged in to a Wiz answering every
+// GraphQL request with handler.
+func newGraphQLTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			json.NewEncoder(w).Encode(TokenResponse{AccessToken: "token", ExpiresIn: 3600})
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := CreateClient(context.Background(), ClientConfig{
+		Credentials: ClientCredentials{
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Endpoint:     server.URL + "/graphql",
+			AuthURL:      server.URL + "/oauth/token",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateClient() error = %s", err)
+	}
+	return client
+}
+
+func TestDeleteWizProjectNotFound(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{"deleted", `{"data":{"deleteProject":{"_stub":null}}}`, false, false},
+		{"already deleted", `{"data":null,"errors":[{"message":"Project not found"}]}`, true, true},
+		{"other error", `{"data":null,"errors":[{"message":"Project has linked resources"}]}`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newGraphQLTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.DeleteWizProject(context.Background(), DeleteProjectRequest{Input: DeleteProjectInput{ID: "p1"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteWizProject() error = %v, want error %t", err, tt.wantErr)
+			}
+			if got := errorsHandler.NotFoundError(err); got != tt.wantNotFound {
+				t.Errorf("DeleteWizProject() error = %v, not found %t, want %t", err, got, tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func TestArchiveWizProjectNotFound(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{"archived", `{"data":{"updateProject":{"project":{"id":"p1"}}}}`, false, false},
+		{"already deleted", `{"data":null,"errors":[{"message":"Resource not found"}]}`, true, true},
+		{"other error", `{"data":null,"errors":[{"message":"Project is a folder"}]}`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newGraphQLTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.ArchiveWizProject(context.Background(), ArchiveProjectRequest{
+				Input: ArchiveProjectInput{ID: "p1", Patch: ArchivePatch{Archived: true}},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ArchiveWizProject() error = %v, want error %t", err, tt.wantErr)
+			}
+			if got := errorsHandler.NotFoundError(err); got != tt.wantNotFound {
+				t.Errorf("ArchiveWizProject() error = %v, not found %t, want %t", err, got, tt.wantNotFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package apiClient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FindRepositoryID returns the ID of the repository fullName, e.g.
+// "my-org/my-repo", scanned by the version control connector connectorID.
+func (c *Client) FindRepositoryID(ctx context.Context, connectorID string, fullName string) (string, error) {
+	// any match beyond the first makes the lookup ambiguous
+	entities, err := c.searchGraph(ctx, Query{
+		Type: []string{
+			"REPOSITORY",
+		},
+		Where: map[string]interface{}{
+			"name": map[string]interface{}{
+				"EQUALS": []string{fullName},
+			},
+			"connectorId": map[string]interface{}{
+				"EQUALS": []string{connectorID},
+			},
+		},
+	}, PaginateOptions{MaxResults: 1})
+
+	if err != nil && !errors.Is(err, ErrMaxResultsReached) {
+		return "", err
+	}
+
+	var ids []string
+	for _, entity := range entities {
+		if entity.ID != nil {
+			ids = append(ids, *entity.ID)
+		}
+	}
+
+	switch {
+	case errors.Is(err, ErrMaxResultsReached):
+		return "", fmt.Errorf("more than one repository %q found for connector %q", fullName, connectorID)
+	case len(ids) == 0:
+		return "", fmt.Errorf("no repository %q found for connector %q", fullName, connectorID)
+	default:
+		return ids[0], nil
+	}
+}
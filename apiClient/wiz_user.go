@@ -0,0 +1,86 @@
+package apiClient
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fatih/structs"
+)
+
+// #region Get Users Request Struct
+type GetUsersRequest struct {
+	First    int64       `structs:"first"`
+	After    *string     `structs:"after"`
+	FilterBy UserFilters `structs:"filterBy"`
+}
+
+type UserFilters struct {
+	Search string `structs:"search"`
+}
+
+// #endregion
+
+// #region Get Users Response Struct
+type GetUsersResponseData struct {
+	Users Connection[User] `json:"users"`
+}
+
+// #endregion
+
+func (c *Client) GetUsers(ctx context.Context, req GetUsersRequest) (*GetUsersResponseData, error) {
+	get_req := `
+	query Users($first: Int, $after: String, $filterBy: UserFilters) {
+		users(first: $first, after: $after, filterBy: $filterBy) {
+		nodes {
+			id
+			name
+			email
+		}
+		pageInfo {
+			endCursor
+			hasNextPage
+		}
+		}
+	}
+	  `
+	s := structs.New(req)
+	request_mapped := s.Map()
+	response := &GetUsersResponseData{}
+	if err := c.doRequest(ctx, get_req, request_mapped, response); err != nil {
+		return nil, c.handleReadError(err, req.FilterBy.Search, "user")
+	}
+
+	return response, nil
+}
+
+// FindUserByEmail returns the user whose email address is email, ignoring
+// case, or nil when there is none.
+func (c *Client) FindUserByEmail(ctx context.Context, email string) (*User, error) {
+	// the search also matches names and partial addresses, so the exact
+	// address is picked from its results, paging no further than its match
+	var user *User
+	_, err := Paginate(ctx, func(ctx context.Context, first int, after *string) (*Connection[User], error) {
+		response, err := c.GetUsers(ctx, GetUsersRequest{
+			First:    int64(first),
+			After:    after,
+			FilterBy: UserFilters{Search: email},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		page := response.Users
+		for i := range page.Nodes {
+			if strings.EqualFold(page.Nodes[i].Email, email) {
+				user = &page.Nodes[i]
+				page.PageInfo.HasNextPage = false
+				break
+			}
+		}
+		return &page, nil
+	}, PaginateOptions{})
+	if user != nil {
+		return user, nil
+	}
+	return nil, err
+}
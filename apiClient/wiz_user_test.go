@@ -0,0 +1,65 @@
+package apiClient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestFindUserByEmail(t *testing.T) {
+	// pages of the users matching a search for jane.doe@example.com
+	pages := [][]User{
+		{{ID: "u1", Email: "jane.doe@example.com.invalid"}, {ID: "u2", Email: "Jane Doe <jane@example.com>"}},
+		{{ID: "u3", Email: "old.jane.doe@example.com"}, {ID: "u4", Email: "Jane.Doe@Example.com"}},
+		{{ID: "u5", Email: "jane.doe@example.com"}},
+	}
+
+	tests := []struct {
+		name         string
+		email        string
+		want         string
+		wantRequests int
+	}{
+		{"match stops paging", "jane.doe@example.com", "u4", 2},
+		{"no match reads every page", "john.doe@example.com", "", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newGraphQLTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var request GraphQLRequest
+				json.NewDecoder(r.Body).Decode(&request)
+				page := 0
+				if after, ok := request.Variables["after"].(string); ok {
+					page, _ = strconv.Atoi(after)
+				}
+				requests++
+
+				users := Connection[User]{Nodes: pages[page]}
+				users.PageInfo.HasNextPage = page+1 < len(pages)
+				users.PageInfo.EndCursor = fmt.Sprint(page + 1)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": GetUsersResponseData{Users: users},
+				})
+			})
+
+			user, err := client.FindUserByEmail(context.Background(), tt.email)
+			if err != nil {
+				t.Fatalf("FindUserByEmail() error = %s", err)
+			}
+			var got string
+			if user != nil {
+				got = user.ID
+			}
+			if got != tt.want {
+				t.Errorf("FindUserByEmail() = %q, want %q", got, tt.want)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("FindUserByEmail() requested %d pages, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -2,60 +2,248 @@ package errors
 
 import (
 	"fmt"
-	"github.com/pkg/errors"
 	"net/http"
 	"regexp"
-	"strconv"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
-func NotFoundError(err error) bool {
-	notFoundErr := "(?i)not Found"
-	expectedErr := regexp.MustCompile(notFoundErr)
-	return expectedErr.Match([]byte(err.Error()))
+var (
+	// ErrInvalidCredentials means the auth server rejected the client_id or
+	// client_secret.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAuthServerUnreachable means no response was received from the auth
+	// server.
+	ErrAuthServerUnreachable = errors.New("auth server unreachable")
+	// ErrUnexpectedAuthResponse means the auth server answered with
+	// something other than an access token.
+	ErrUnexpectedAuthResponse = errors.New("unexpected auth server response")
+)
+
+// AuthError is returned when logging in to Wiz fails. Kind is one of the
+// ErrInvalidCredentials, ErrAuthServerUnreachable or ErrUnexpectedAuthResponse
+// sentinels, so callers can test for it with errors.Is.
+type AuthError struct {
+	Kind       error
+	URL        string
+	StatusCode int
+	// OAuthError and OAuthErrorDescription are the error and
+	// error_description fields of an OAuth error response, if any.
+	OAuthError            string
+	OAuthErrorDescription string
+	Err                   error
 }
 
-func FailedValidationError(err error) bool {
-	dataValidationError := "(?i)data validation failed"
-	expectedErr := regexp.MustCompile(dataValidationError)
-	return expectedErr.Match([]byte(err.Error()))
-}
-
-func ExtractErrorCode(err error) (int, error) {
-	// error returned from machinebox/graphql is of graphql type
-	// errorNon200Template = "graphql: server returned a non-200 status code: 503"
-	rootError := err
-	if strings.Contains(err.Error(), "graphql") {
-		errorStringArray := strings.Split(err.Error(), ":")
-		if len(errorStringArray) == 3 {
-			errCodeString := strings.TrimSpace(errorStringArray[2])
-			errCode, err := strconv.ParseUint(errCodeString, 10, 32)
-			if err != nil {
-				return 0, rootError
-			}
-			return int(errCode), nil
+func (e *AuthError) Error() string {
+	msg := e.Kind.Error()
+	if e.StatusCode != 0 {
+		msg = fmt.Sprintf("%s (HTTP %d)", msg, e.StatusCode)
+	}
+	if e.OAuthError != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.OAuthError)
+	}
+	if e.OAuthErrorDescription != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.OAuthErrorDescription)
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %s", msg, e.Err.Error())
+	}
+	return msg
+}
+
+func (e *AuthError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// APIError describes a failed Wiz API request. It is built from the HTTP
+// response, and, for GraphQL errors, from the first entry of its "errors"
+// list. APIError is embedded in the typed errors below, which callers match
+// with errors.As.
+type APIError struct {
+	StatusCode int
+	// Code is the GraphQL extensions.code of the error, e.g. NOT_FOUND.
+	Code string
+	// Path is the GraphQL path of the field that failed, e.g.
+	// ["createProject", "input", "cloudAccountLinks", 2, "environment"].
+	Path      []interface{}
+	Message   string
+	RequestID string
+	// Errors lists every GraphQL error of the response, the first of which
+	// is also described by Code, Path and Message.
+	Errors []GraphQLError
+}
+
+// GraphQLError is a single entry of the "errors" list of a GraphQL response.
+type GraphQLError struct {
+	Message    string        `json:"message"`
+	Path       []interface{} `json:"path"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+func (e *APIError) Error() string {
+	var details []string
+	if e.StatusCode != 0 {
+		details = append(details, fmt.Sprintf("HTTP %d", e.StatusCode))
+	}
+	if e.Code != "" {
+		details = append(details, "code "+e.Code)
+	}
+	if e.RequestID != "" {
+		details = append(details, "request ID "+e.RequestID)
+	}
+
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if len(details) > 0 {
+		msg = fmt.Sprintf("%s (%s)", msg, strings.Join(details, ", "))
+	}
+	return msg
+}
+
+type NotFound struct{ *APIError }
+
+type Unauthorized struct{ *APIError }
+
+type Forbidden struct{ *APIError }
+
+type Validation struct{ *APIError }
+
+type RateLimited struct{ *APIError }
+
+func (e *RateLimited) Error() string {
+	return e.APIError.Error() + ". The Wiz API rate limit was exceeded, please wait a few minutes and try again."
+}
+
+type ServerError struct{ *APIError }
+
+func (e *ServerError) Error() string {
+	return e.APIError.Error() + ". The Wiz API is unavailable, please wait a few minutes and try again."
+}
+
+// NewAPIError builds the typed error of a Wiz API response from its status,
+// headers and GraphQL errors, or returns nil when the request succeeded.
+func NewAPIError(statusCode int, header http.Header, graphqlErrors []GraphQLError) error {
+	if len(graphqlErrors) == 0 && statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		RequestID:  requestID(header),
+		Errors:     graphqlErrors,
+	}
+	if len(graphqlErrors) > 0 {
+		apiErr.Message = graphqlErrors[0].Message
+		apiErr.Path = graphqlErrors[0].Path
+		apiErr.Code = graphqlErrors[0].Extensions.Code
+	}
+
+	return classify(apiErr)
+}
+
+func requestID(header http.Header) string {
+	for _, name := range []string{"X-Request-Id", "X-Amzn-Requestid", "X-Amzn-Trace-Id"} {
+		if id := header.Get(name); id != "" {
+			return id
 		}
 	}
-	return 0, rootError
+	return ""
+}
+
+var (
+	notFoundMessage   = regexp.MustCompile("(?i)not found")
+	validationMessage = regexp.MustCompile("(?i)data validation failed")
+)
+
+// classify types apiErr from its GraphQL error code, or failing that from its
+// HTTP status, and only as a last resort from its message.
+func classify(apiErr *APIError) error {
+	switch apiErr.Code {
+	case "NOT_FOUND":
+		return &NotFound{apiErr}
+	case "UNAUTHENTICATED":
+		return &Unauthorized{apiErr}
+	case "FORBIDDEN", "UNAUTHORIZED":
+		return &Forbidden{apiErr}
+	case "BAD_USER_INPUT", "INVALID_INPUT", "GRAPHQL_VALIDATION_FAILED":
+		return &Validation{apiErr}
+	case "RATE_LIMIT_EXCEEDED":
+		return &RateLimited{apiErr}
+	case "INTERNAL", "INTERNAL_SERVER_ERROR":
+		return &ServerError{apiErr}
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound:
+		return &NotFound{apiErr}
+	case apiErr.StatusCode == http.StatusUnauthorized:
+		return &Unauthorized{apiErr}
+	case apiErr.StatusCode == http.StatusForbidden:
+		return &Forbidden{apiErr}
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		return &RateLimited{apiErr}
+	case apiErr.StatusCode >= 500:
+		return &ServerError{apiErr}
+	case apiErr.StatusCode == http.StatusBadRequest,
+		apiErr.StatusCode == http.StatusUnprocessableEntity:
+		return &Validation{apiErr}
+	case notFoundMessage.MatchString(apiErr.Message):
+		return &NotFound{apiErr}
+	case validationMessage.MatchString(apiErr.Message):
+		return &Validation{apiErr}
+	}
+	return apiErr
 }
 
-func BuildErrorMessage(err error) error {
-	// if it's a Not Found error, we return the actual graphql error.
-	if NotFoundError(err) {
-		return err
+// projectNotFoundMessage matches the message of the error returned when the
+// project itself of a project query does not exist.
+var projectNotFoundMessage = regexp.MustCompile(`(?i)^(project|resource) not found`)
+
+// ProjectNotFound returns err as a NotFound error when it is an unclassified
+// API error saying that the project looked up does not exist, and err
+// otherwise. It is only meant for the lookup of a single project by ID,
+// where such a message cannot refer to another object.
+func ProjectNotFound(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && projectNotFoundMessage.MatchString(apiErr.Message) {
+		return &NotFound{apiErr}
 	}
-	errCode, err := ExtractErrorCode(err)
-	// if we fail to decode the error code, just return the error directly
-	if http.StatusText(errCode) == "" {
-		return err
+	return err
+}
+
+func NotFoundError(err error) bool {
+	var notFound *NotFound
+	return errors.As(err, &notFound)
+}
+
+func FailedValidationError(err error) bool {
+	var validation *Validation
+	return errors.As(err, &validation)
+}
+
+func UnauthorizedError(err error) bool {
+	var unauthorized *Unauthorized
+	return errors.As(err, &unauthorized)
+}
+
+// ValidationErrors returns the GraphQL errors of a failed validation, or nil
+// when err is not a validation error.
+func ValidationErrors(err error) []GraphQLError {
+	var validation *Validation
+	if !errors.As(err, &validation) {
+		return nil
 	}
-	var errString string
-	if int(errCode) == 502 || int(errCode) == 503 || int(errCode) == 504 {
-		// retryable error codes - [502, 503, 504]
-		errString = fmt.Sprintf("The server returned a %s error (%v). Please wait a few minutes and try again.", http.StatusText(errCode), errCode)
-	} else {
-		// non-retryable errors
-		errString = fmt.Sprintf("The server returned a %s error (%v). Please contact Turbot support.", http.StatusText(errCode), errCode)
+	if len(validation.Errors) == 0 {
+		return []GraphQLError{{Message: validation.Message, Path: validation.Path}}
 	}
-	return errors.New(errString)
+	return validation.Errors
 }
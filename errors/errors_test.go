@@ -0,0 +1,179 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func graphqlError(message string, code string) GraphQLError {
+	e := GraphQLError{Message: message}
+	e.Extensions.Code = code
+	return e
+}
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		errors     []GraphQLError
+		want       string
+	}{
+		{"success", 200, nil, "<nil>"},
+		{"not found code", 200, []GraphQLError{graphqlError("Resource not found", "NOT_FOUND")}, "*errors.NotFound"},
+		{"unauthenticated code", 200, []GraphQLError{graphqlError("token expired", "UNAUTHENTICATED")}, "*errors.Unauthorized"},
+		{"forbidden code", 200, []GraphQLError{graphqlError("access denied", "FORBIDDEN")}, "*errors.Forbidden"},
+		{"unauthorized code", 200, []GraphQLError{graphqlError("access denied", "UNAUTHORIZED")}, "*errors.Forbidden"},
+		{"bad user input code", 200, []GraphQLError{graphqlError("invalid", "BAD_USER_INPUT")}, "*errors.Validation"},
+		{"invalid input code", 200, []GraphQLError{graphqlError("invalid", "INVALID_INPUT")}, "*errors.Validation"},
+		{"graphql validation code", 400, []GraphQLError{graphqlError("unknown field", "GRAPHQL_VALIDATION_FAILED")}, "*errors.Validation"},
+		{"rate limit code", 200, []GraphQLError{graphqlError("slow down", "RATE_LIMIT_EXCEEDED")}, "*errors.RateLimited"},
+		{"internal code", 200, []GraphQLError{graphqlError("oops", "INTERNAL_SERVER_ERROR")}, "*errors.ServerError"},
+		{"code wins over status", 500, []GraphQLError{graphqlError("Resource not found", "NOT_FOUND")}, "*errors.NotFound"},
+		{"404 status", 404, nil, "*errors.NotFound"},
+		{"401 status", 401, nil, "*errors.Unauthorized"},
+		{"403 status", 403, nil, "*errors.Forbidden"},
+		{"429 status", 429, nil, "*errors.RateLimited"},
+		{"503 status", 503, nil, "*errors.ServerError"},
+		{"400 status", 400, nil, "*errors.Validation"},
+		{"422 status", 422, nil, "*errors.Validation"},
+		{"not found in message only", 200, []GraphQLError{graphqlError("parent project not found", "")}, "*errors.NotFound"},
+		{"validation in message only", 200, []GraphQLError{graphqlError("Data validation failed", "")}, "*errors.Validation"},
+		{"status wins over message", 403, []GraphQLError{graphqlError("Project not found", "")}, "*errors.Forbidden"},
+		{"unknown code", 200, []GraphQLError{graphqlError("something", "SOMETHING")}, "*errors.APIError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{"X-Request-Id": []string{"req-1"}}
+			err := NewAPIError(tt.statusCode, header, tt.errors)
+			if got := fmt.Sprintf("%T", err); got != tt.want {
+				t.Fatalf("NewAPIError() = %s, want %s", got, tt.want)
+			}
+			if err == nil {
+				return
+			}
+
+			apiErr := apiErrorOf(err)
+			if apiErr.StatusCode != tt.statusCode || apiErr.RequestID != "req-1" {
+				t.Errorf("APIError = %+v, want status %d and request ID req-1", apiErr, tt.statusCode)
+			}
+		})
+	}
+}
+
+// apiErrorOf returns the APIError itself or embedded in one of the typed
+// errors.
+func TestNewAPIErrorDetails(t *testing.T) {
+	invalidEnvironment := graphqlError("invalid environment", "BAD_USER_INPUT")
+	invalidEnvironment.Path = []interface{}{"createProject", "input", "cloudAccountLinks", 2, "environment"}
+	header := http.Header{"X-Amzn-Requestid": []string{"req-2"}}
+
+	apiErr := apiErrorOf(NewAPIError(200, header, []GraphQLError{invalidEnvironment, graphqlError("invalid name", "")}))
+	if apiErr.Message != "invalid environment" || apiErr.Code != "BAD_USER_INPUT" || len(apiErr.Path) != 5 ||
+		apiErr.RequestID != "req-2" || len(apiErr.Errors) != 2 {
+		t.Errorf("APIError = %+v, want the first of both GraphQL errors and request ID req-2", apiErr)
+	}
+	if want := "invalid environment (HTTP 200, code BAD_USER_INPUT, request ID req-2)"; apiErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", apiErr.Error(), want)
+	}
+}
+
+// apiErrorOf returns the APIError itself or embedded in one of the typed
+// errors.
+func apiErrorOf(err error) *APIError {
+	switch e := err.(type) {
+	case *APIError:
+		return e
+	case *NotFound:
+		return e.APIError
+	case *Unauthorized:
+		return e.APIError
+	case *Forbidden:
+		return e.APIError
+	case *Validation:
+		return e.APIError
+	case *RateLimited:
+		return e.APIError
+	case *ServerError:
+		return e.APIError
+	}
+	return nil
+}
+
+func TestErrorPredicates(t *testing.T) {
+	notFound := NewAPIError(200, nil, []GraphQLError{graphqlError("Resource not found", "NOT_FOUND")})
+	validation := NewAPIError(200, nil, []GraphQLError{graphqlError("invalid", "BAD_USER_INPUT")})
+	unauthorized := NewAPIError(401, nil, nil)
+
+	tests := []struct {
+		name         string
+		err          error
+		notFound     bool
+		validation   bool
+		unauthorized bool
+	}{
+		{"nil", nil, false, false, false},
+		{"not found", notFound, true, false, false},
+		{"wrapped not found", fmt.Errorf("error reading project: %w", notFound), true, false, false},
+		{"validation", validation, false, true, false},
+		{"unauthorized", unauthorized, false, false, true},
+		{"plain error", errors.New("not found"), false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotFoundError(tt.err); got != tt.notFound {
+				t.Errorf("NotFoundError() = %t, want %t", got, tt.notFound)
+			}
+			if got := FailedValidationError(tt.err); got != tt.validation {
+				t.Errorf("FailedValidationError() = %t, want %t", got, tt.validation)
+			}
+			if got := UnauthorizedError(tt.err); got != tt.unauthorized {
+				t.Errorf("UnauthorizedError() = %t, want %t", got, tt.unauthorized)
+			}
+		})
+	}
+}
+
+func TestProjectNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"project not found", NewAPIError(200, nil, []GraphQLError{graphqlError("Project not found", "")}), true},
+		{"resource not found", NewAPIError(200, nil, []GraphQLError{graphqlError("Resource not found", "")}), true},
+		{"other error", NewAPIError(200, nil, []GraphQLError{graphqlError("Project is archived", "")}), false},
+		{"classified error", NewAPIError(403, nil, []GraphQLError{graphqlError("Project not found", "")}), false},
+		{"plain error", errors.New("Project not found"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NotFoundError(ProjectNotFound(tt.err)); got != tt.want {
+				t.Errorf("NotFoundError(ProjectNotFound()) = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	first := graphqlError("name is required", "BAD_USER_INPUT")
+	second := graphqlError("environment is invalid", "BAD_USER_INPUT")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not a validation error", NewAPIError(404, nil, nil), 0},
+		{"status only", NewAPIError(400, nil, nil), 1},
+		{"every graphql error", NewAPIError(200, nil, []GraphQLError{first, second}), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidationErrors(tt.err); len(got) != tt.want {
+				t.Errorf("ValidationErrors() returned %d errors, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,63 @@
+resource "wiz_project" "business_unit" {
+  name      = "Finance"
+  is_folder = true
+}
+
 resource "wiz_project" "this" {
     name = "tf_test"
+    description   = "Payments platform"
+    business_unit = "Finance"
+    identifiers   = ["payments"]
+    parent_project_id = wiz_project.business_unit.id
    
+    cloud_organization_links = [
+    {
+      cloud_organization_guid = "0b1c6c1e-6b3a-5f5b-b3c2-5b8b33ff1f4f"
+      environment             = "STAGING"
+    },
+  ]
+
     cloud_account_links = [
     { 
       cloud_account_guid = "3225def3-0e0e-5cb8-955a-3583f696f778",
       environment = "DEVELOPMENT" , 
       shared = false
     },
+    {
+      cloud_account_guid = "5b8b33ff-6b3a-5f5b-b3c2-1f4f0b1c6c1e"
+      environment        = "PRODUCTION"
+      shared             = true
+      resource_tags = [
+        { key = "team", value = "payments" },
+      ]
+      resource_groups = ["payments-prod"]
+    },
 
   ]
-}
 
+  kubernetes_cluster_links = [
+    {
+      kubernetes_cluster_guid = "9f3c1a52-7d4e-5b1a-8c2f-3e6d9b0a4c71"
+      namespaces              = ["payments", "payments-jobs"]
+      shared                  = true
+    },
+  ]
+
+  repository_links = [
+    {
+      vcs_connector_id     = "c2d5f7a1-3b4e-4f6a-9d8c-1e2f3a4b5c6d"
+      repository_full_name = "my-org/payments-api"
+    },
+  ]
+
+  project_owners     = ["jane.doe@example.com"]
+  security_champions = ["john.doe@example.com"]
+
+  risk_profile = {
+    business_impact      = "MBI"
+    is_internet_facing   = "YES"
+    stores_data          = "YES"
+    sensitive_data_types = ["CUSTOMER", "FINANCIAL"]
+    regulatory_standards = ["SOC"]
+  }
+}
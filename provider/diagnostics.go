@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
+)
+
+// inputPathInMessage matches the location of an invalid input field as
+// reported in GraphQL validation messages, e.g.
+// `... at "input.cloudAccountLinks[2].environment"; ...`.
+var inputPathInMessage = regexp.MustCompile(`at "([^"]+)"`)
+
+// inputWrappers are the path segments that wrap the fields of a mutation
+// input and have no counterpart in the Terraform schema.
+var inputWrappers = map[string]bool{
+	"input":    true,
+	"override": true,
+	"patch":    true,
+}
+
+// addAPIErrorDiagnostics reports err on resp. Validation errors that point at
+// an input field are reported on the matching Terraform attribute, all other
+// errors are reported on the resource as a whole.
+//
+// API field names are converted to snake_case to find their attribute;
+// fieldNames lists the fields of the resource whose attribute is named
+// differently, e.g. "cloudAccount" for "cloud_account_guid".
+func addAPIErrorDiagnostics(diags *diag.Diagnostics, schema tfsdk.Schema, fieldNames map[string]string, summary string, detail string, err error) {
+	validationErrors := errorsHandler.ValidationErrors(err)
+	if validationErrors == nil {
+		diags.AddError(summary, fmt.Sprintf("%s, got error: %s", detail, err))
+		return
+	}
+
+	for _, validationError := range validationErrors {
+		path := attributePathOf(schema, fieldNames, validationError)
+		if path == nil {
+			diags.AddError(summary, fmt.Sprintf("%s, got error: %s", detail, validationError.Message))
+			continue
+		}
+		diags.AddAttributeError(path, summary, fmt.Sprintf("%s, got error: %s", detail, validationError.Message))
+	}
+}
+
+// attributePathOf translates the GraphQL path of a validation error into the
+// longest matching attribute path of schema, or nil when nothing matches.
+func attributePathOf(schema tfsdk.Schema, fieldNames map[string]string, validationError errorsHandler.GraphQLError) *tftypes.AttributePath {
+	segments := validationError.Path
+	if match := inputPathInMessage.FindStringSubmatch(validationError.Message); match != nil {
+		segments = splitInputPath(match[1])
+	}
+
+	// only the fields below the mutation input map onto attributes
+	start := -1
+	for i, segment := range segments {
+		if name, ok := segment.(string); ok && inputWrappers[name] {
+			start = i + 1
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	var path *tftypes.AttributePath
+	current := tftypes.NewAttributePath()
+	for _, segment := range segments[start:] {
+		switch s := segment.(type) {
+		case string:
+			name, ok := fieldNames[s]
+			if !ok {
+				name = toSnakeCase(s)
+			}
+			current = current.WithAttributeName(name)
+		case float64:
+			current = current.WithElementKeyInt(int(s))
+		case int:
+			current = current.WithElementKeyInt(s)
+		default:
+			return path
+		}
+
+		if _, err := schema.AttributeTypeAtPath(current); err != nil {
+			break
+		}
+		path = current
+	}
+	return path
+}
+
+// splitInputPath splits a dotted input path such as
+// "input.cloudAccountLinks[2].environment" into its segments.
+func splitInputPath(inputPath string) []interface{} {
+	var segments []interface{}
+	for _, field := range strings.Split(inputPath, ".") {
+		name := field
+		var indexes []interface{}
+		if open := strings.Index(field, "["); open >= 0 {
+			name = field[:open]
+			for _, index := range strings.Split(strings.TrimSuffix(field[open+1:], "]"), "][") {
+				if i, err := strconv.Atoi(index); err == nil {
+					indexes = append(indexes, i)
+				}
+			}
+		}
+		segments = append(segments, name)
+		segments = append(segments, indexes...)
+	}
+	return segments
+}
+
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// keep acronyms such as "API" together
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
+)
+
+func TestAttributePathOf(t *testing.T) {
+	schema, diags := resourceWizProjectType{}.GetSchema(context.Background())
+	if diags.HasError() {
+		t.Fatalf("GetSchema() diagnostics: %v", diags)
+	}
+	root := tftypes.NewAttributePath()
+
+	tests := []struct {
+		name    string
+		message string
+		path    []interface{}
+		want    *tftypes.AttributePath
+	}{
+		{
+			name: "graphql path",
+			path: []interface{}{"createProject", "input", "name"},
+			want: root.WithAttributeName("name"),
+		},
+		{
+			name: "nested graphql path with renamed field",
+			path: []interface{}{"updateProject", "input", "override", "cloudAccountLinks", float64(2), "cloudAccount"},
+			want: root.WithAttributeName("cloud_account_links").WithElementKeyInt(2).WithAttributeName("cloud_account_guid"),
+		},
+		{
+			name:    "path in message",
+			message: `Variable "$input" got invalid value "QA" at "input.cloudAccountLinks[1].environment"; Value "QA" does not exist`,
+			want:    root.WithAttributeName("cloud_account_links").WithElementKeyInt(1).WithAttributeName("environment"),
+		},
+		{
+			name:    "message wins over graphql path",
+			message: `invalid value at "input.riskProfile.businessImpact"`,
+			path:    []interface{}{"createProject"},
+			want:    root.WithAttributeName("risk_profile").WithAttributeName("business_impact"),
+		},
+		{
+			name: "unknown field keeps longest known prefix",
+			path: []interface{}{"createProject", "input", "riskProfile", "somethingNew"},
+			want: root.WithAttributeName("risk_profile"),
+		},
+		{
+			name: "acronym",
+			path: []interface{}{"createProject", "input", "riskProfile", "hasExposedAPI"},
+			want: root.WithAttributeName("risk_profile").WithAttributeName("has_exposed_api"),
+		},
+		{
+			name: "no input wrapper",
+			path: []interface{}{"createProject", "name"},
+			want: nil,
+		},
+		{
+			name: "unknown top level field",
+			path: []interface{}{"createProject", "input", "somethingNew"},
+			want: nil,
+		},
+		{
+			name: "no path",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := attributePathOf(schema, wizProjectFieldNames, errorsHandler.GraphQLError{Message: tt.message, Path: tt.path})
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("attributePathOf() = %s, want nil", got)
+			case tt.want != nil && (got == nil || !got.Equal(tt.want)):
+				t.Errorf("attributePathOf() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitInputPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []interface{}
+	}{
+		{"input.name", []interface{}{"input", "name"}},
+		{"input.cloudAccountLinks[2].environment", []interface{}{"input", "cloudAccountLinks", 2, "environment"}},
+		{"input.matrix[1][3]", []interface{}{"input", "matrix", 1, 3}},
+		{"input.links[x]", []interface{}{"input", "links"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := splitInputPath(tt.path)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitInputPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("splitInputPath(%q) = %v, want %v", tt.path, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"name", "name"},
+		{"businessImpact", "business_impact"},
+		{"cloudAccountLinks", "cloud_account_links"},
+		{"hasExposedAPI", "has_exposed_api"},
+		{"APIKey", "api_key"},
+		{"isActivelyDeveloped", "is_actively_developed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toSnakeCase(tt.name); got != tt.want {
+				t.Errorf("toSnakeCase(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddAPIErrorDiagnostics(t *testing.T) {
+	schema, _ := resourceWizProjectType{}.GetSchema(context.Background())
+
+	validation := errorsHandler.NewAPIError(400, nil, []errorsHandler.GraphQLError{
+		{Message: "invalid name", Path: []interface{}{"createProject", "input", "name"}},
+		{Message: "invalid field", Path: []interface{}{"createProject", "input", "somethingNew"}},
+	})
+
+	tests := []struct {
+		name          string
+		err           error
+		wantAttribute int
+		wantResource  int
+	}{
+		{"validation errors", validation, 1, 1},
+		{"other error", errors.New("connection reset"), 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addAPIErrorDiagnostics(&diags, schema, wizProjectFieldNames, "summary", "detail", tt.err)
+
+			var attribute, resource int
+			for _, d := range diags {
+				if _, ok := d.(diag.DiagnosticWithPath); ok {
+					attribute++
+				} else {
+					resource++
+				}
+			}
+			if attribute != tt.wantAttribute || resource != tt.wantResource {
+				t.Errorf("got %d attribute and %d resource diagnostics, want %d and %d",
+					attribute, resource, tt.wantAttribute, tt.wantResource)
+			}
+		})
+	}
+}
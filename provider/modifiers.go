@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 type boolDefaultModifier struct {
@@ -85,3 +87,148 @@ func (m stringDefaultModifier) Modify(ctx context.Context, req tfsdk.ModifyAttri
 
 	resp.AttributePlan = types.String{Value: m.Default}
 }
+
+// repositoryIDModifier is a plan modifier for the repository_id attribute of
+// repository links. When the link names its repository by connector and full
+// name instead, it plans the ID resolved by the previous apply, as long as the
+// link at the same position still names the same repository. Otherwise the ID
+// stays unknown until it is resolved on apply.
+type repositoryIDModifier struct{}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (m repositoryIDModifier) Description(ctx context.Context) string {
+	return "If value is not configured, keeps the repository ID resolved from vcs_connector_id and repository_full_name"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (m repositoryIDModifier) MarkdownDescription(ctx context.Context) string {
+	return "If value is not configured, keeps the repository ID resolved from `vcs_connector_id` and `repository_full_name`"
+}
+
+// Modify runs the logic of the plan modifier.
+func (m repositoryIDModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest, resp *tfsdk.ModifyAttributePlanResponse) {
+	var id types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributePlan, &id)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	if !id.Unknown || req.State.Raw.IsNull() {
+		return
+	}
+
+	link := req.AttributePath.WithoutLastStep()
+	sameValue := func(name string) bool {
+		var configured, current types.String
+		// the link may not exist in state, which is no error here
+		if req.Config.GetAttribute(ctx, link.WithAttributeName(name), &configured).HasError() ||
+			req.State.GetAttribute(ctx, link.WithAttributeName(name), &current).HasError() {
+			return false
+		}
+		return !configured.Null && !configured.Unknown && configured.Equal(current)
+	}
+	if !sameValue("vcs_connector_id") || !sameValue("repository_full_name") {
+		return
+	}
+
+	var current types.String
+	if req.State.GetAttribute(ctx, req.AttributePath, &current).HasError() || current.Null || current.Unknown {
+		return
+	}
+	resp.AttributePlan = current
+}
+
+// useStateUnlessChangedModifier is a plan modifier that, like
+// tfsdk.UseStateForUnknown, plans the prior state value of a computed
+// attribute, but only as long as the configured value of the attribute it is
+// derived from is unchanged. The slug of a project, for instance, follows its
+// name.
+type useStateUnlessChangedModifier struct {
+	Attribute string
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (m useStateUnlessChangedModifier) Description(ctx context.Context) string {
+	return fmt.Sprintf("Keeps the prior state value unless %s changes", m.Attribute)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (m useStateUnlessChangedModifier) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Keeps the prior state value unless `%s` changes", m.Attribute)
+}
+
+// Modify runs the logic of the plan modifier.
+func (m useStateUnlessChangedModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest, resp *tfsdk.ModifyAttributePlanResponse) {
+	if req.AttributeState == nil || req.AttributeConfig == nil || req.State.Raw.IsNull() {
+		return
+	}
+
+	var current, planned types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributePlan, &planned)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || !planned.Unknown {
+		return
+	}
+	diags = tfsdk.ValueAs(ctx, req.AttributeState, &current)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || current.Unknown {
+		return
+	}
+
+	path := tftypes.NewAttributePath().WithAttributeName(m.Attribute)
+	var configured, previous attr.Value
+	diags = req.Config.GetAttribute(ctx, path, &configured)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+	diags = req.State.GetAttribute(ctx, path, &previous)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || !configured.Equal(previous) {
+		return
+	}
+
+	resp.AttributePlan = current
+}
+
+// objectDefaultModifier is a plan modifier that plans a default value for a
+// nested attribute that is not configured. Unlike tfsdk.UseStateForUnknown,
+// removing the attribute from the configuration restores the default rather
+// than keeping the prior state. Attrs holds a value for every nested
+// attribute; their types are those of the schema.
+type objectDefaultModifier struct {
+	Attrs map[string]attr.Value
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (m objectDefaultModifier) Description(ctx context.Context) string {
+	return "If value is not configured, defaults to the documented default of each nested attribute"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (m objectDefaultModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+// Modify runs the logic of the plan modifier.
+func (m objectDefaultModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest, resp *tfsdk.ModifyAttributePlanResponse) {
+	var config types.Object
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &config)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || !config.Null {
+		return
+	}
+
+	var plan types.Object
+	diags = tfsdk.ValueAs(ctx, req.AttributePlan, &plan)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	resp.AttributePlan = types.Object{
+		AttrTypes: plan.AttrTypes,
+		Attrs:     m.Attrs,
+	}
+}
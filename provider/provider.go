@@ -4,17 +4,21 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"shell.com/terraform-provider-wiz/apiClient"
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
 )
 
 type provider struct {
-	wizClient apiClient.Client
+	wizClient *apiClient.Client
 
 	// configured is set to true at the end of the Configure method.
 	// This can be used in Resource and DataSource implementations to verify
@@ -28,9 +32,18 @@ type provider struct {
 }
 
 type providerData struct {
-	Endpoint     types.String `tfsdk:"endpoint"`
-	ClientId     types.String `tfsdk:"client_id"`
-	ClientSecret types.String `tfsdk:"client_secret"`
+	Endpoint        types.String `tfsdk:"endpoint"`
+	ClientId        types.String `tfsdk:"client_id"`
+	ClientSecret    types.String `tfsdk:"client_secret"`
+	AuthURL         types.String `tfsdk:"auth_url"`
+	AuthAudience    types.String `tfsdk:"auth_audience"`
+	Environment     types.String `tfsdk:"environment"`
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+	Profile         types.String `tfsdk:"profile"`
+	DataCenter      types.String `tfsdk:"data_center"`
+	MaxRetries      types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait    types.Int64  `tfsdk:"retry_max_wait"`
+	RequestTimeout  types.Int64  `tfsdk:"request_timeout"`
 }
 
 func (p *provider) Configure(ctx context.Context, req tfsdk.ConfigureProviderRequest, resp *tfsdk.ConfigureProviderResponse) {
@@ -42,26 +55,92 @@ func (p *provider) Configure(ctx context.Context, req tfsdk.ConfigureProviderReq
 		return
 	}
 
-	if data.ClientId.Null || data.ClientSecret.Null {
-		resp.Diagnostics.AddError("No valid credentials provided.", "Both client_id and client_secret are needed.")
+	// values that depend on other resources are not known until apply, but the
+	// client has to log in while planning already
+	unknownAttributes := []struct {
+		name  string
+		value types.String
+	}{
+		{"endpoint", data.Endpoint},
+		{"data_center", data.DataCenter},
+		{"client_id", data.ClientId},
+		{"client_secret", data.ClientSecret},
+		{"auth_url", data.AuthURL},
+		{"auth_audience", data.AuthAudience},
+		{"environment", data.Environment},
+		{"credentials_file", data.CredentialsFile},
+		{"profile", data.Profile},
+	}
+	if data.MaxRetries.Unknown || data.RetryMaxWait.Unknown || data.RequestTimeout.Unknown {
+		resp.Diagnostics.AddError("Unknown provider configuration value.",
+			"The provider cannot create the Wiz client as max_retries, retry_max_wait or request_timeout is unknown at plan time. Set them to static values.")
+	}
+	for _, attribute := range unknownAttributes {
+		if attribute.value.Unknown {
+			resp.Diagnostics.AddAttributeError(tftypes.NewAttributePath().WithAttributeName(attribute.name),
+				"Unknown provider configuration value.",
+				fmt.Sprintf("The provider cannot create the Wiz client as %s is unknown at plan time. Set it to a static value or use the corresponding environment variable.", attribute.name))
+		}
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	maxRetries := apiClient.DefaultMaxRetries
+	if !data.MaxRetries.Null {
+		maxRetries = int(data.MaxRetries.Value)
+	}
+	retryMaxWait := apiClient.DefaultRetryMaxWait
+	if !data.RetryMaxWait.Null {
+		retryMaxWait = time.Duration(data.RetryMaxWait.Value) * time.Second
+	}
+	var requestTimeout time.Duration
+	if !data.RequestTimeout.Null {
+		requestTimeout = time.Duration(data.RequestTimeout.Value) * time.Second
+	}
+
 	config := apiClient.ClientConfig{
 		Credentials: apiClient.ClientCredentials{
 			ClientID:     data.ClientId.Value,
 			ClientSecret: data.ClientSecret.Value,
 			Endpoint:     data.Endpoint.Value,
+			AuthURL:      data.AuthURL.Value,
+			AuthAudience: data.AuthAudience.Value,
+			Environment:  data.Environment.Value,
+			DataCenter:   data.DataCenter.Value,
 		},
+		CredentialsFile: data.CredentialsFile.Value,
+		Profile:         data.Profile.Value,
+		MaxRetries:      maxRetries,
+		RetryMaxWait:    retryMaxWait,
+		RequestTimeout:  requestTimeout,
 	}
 
-	client, err := apiClient.CreateClient(config)
+	client, err := apiClient.CreateClient(ctx, config)
 	if err != nil {
 		tflog.Error(ctx, fmt.Sprintf("failed to create client: %s", err.Error()))
-		resp.Diagnostics.AddError("failed to create client.", err.Error())
+		var authErr *errorsHandler.AuthError
+		authURL := "the configured auth server"
+		if errors.As(err, &authErr) && authErr.URL != "" {
+			authURL = authErr.URL
+		}
+		switch {
+		case errors.Is(err, errorsHandler.ErrInvalidCredentials):
+			resp.Diagnostics.AddError("Invalid Wiz credentials.",
+				fmt.Sprintf("The Wiz auth server rejected the client_id and client_secret, check they belong to a valid service account: %s", err.Error()))
+		case errors.Is(err, errorsHandler.ErrAuthServerUnreachable):
+			resp.Diagnostics.AddError("Wiz auth server unreachable.",
+				fmt.Sprintf("Could not reach the Wiz auth server at %s, check auth_url and your network: %s", authURL, err.Error()))
+		case errors.Is(err, errorsHandler.ErrUnexpectedAuthResponse):
+			resp.Diagnostics.AddError("Unexpected response from the Wiz auth server.",
+				fmt.Sprintf("Logging in to Wiz at %s failed, check auth_url, auth_audience and environment: %s", authURL, err.Error()))
+		default:
+			resp.Diagnostics.AddError("failed to create client.", err.Error())
+		}
+		return
 	}
 
-	p.wizClient = *client
+	p.wizClient = client
 
 	p.configured = true
 }
@@ -82,17 +161,84 @@ func (p *provider) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostic
 	return tfsdk.Schema{
 		Attributes: map[string]tfsdk.Attribute{
 			"endpoint": {
-				MarkdownDescription: "The base URL of the Wiz API.",
+				MarkdownDescription: "The base URL of the Wiz API, e.g. `https://api.eu1.app.wiz.io/graphql`. Can also be set with the `WIZ_URL` environment variable. Derived from `data_center` when omitted.",
 				Optional:            true,
 				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					urlValidator{},
+				},
+			},
+			"data_center": {
+				MarkdownDescription: "The Wiz data center of the tenant, e.g. `us17` or `eu1`, used to derive `endpoint`.",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					dataCenterValidator{},
+				},
 			},
 			"client_id": {
-				MarkdownDescription: "service account client_id used to log in to Wiz.",
+				MarkdownDescription: "service account client_id used to log in to Wiz. Can also be set with the `WIZ_CLIENT_ID` environment variable.",
 				Optional:            true,
 				Type:                types.StringType,
 			},
 			"client_secret": {
-				MarkdownDescription: "service account secret (client_secret) used to log in to Wiz.",
+				MarkdownDescription: "service account secret (client_secret) used to log in to Wiz. Can also be set with the `WIZ_CLIENT_SECRET` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
+			"auth_url": {
+				MarkdownDescription: "The URL of the Wiz auth server token endpoint. Can also be set with the `WIZ_AUTH_URL` environment variable. Defaults to the auth server of `environment`.",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					urlValidator{},
+				},
+			},
+			"auth_audience": {
+				MarkdownDescription: "The audience requested from the Wiz auth server. Defaults to the audience of `environment`.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"environment": {
+				MarkdownDescription: "The Wiz environment of the tenant, which determines the default `auth_url` and `auth_audience`. One of `commercial` (auth.app.wiz.io), `auth0` (auth.wiz.io), `gov` (auth.gov.wiz.io) or `fedramp` (auth0.gov.wiz.io). When omitted, `fedramp` is used for `gov.wiz.io` endpoints and `auth0` for all others.",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					stringInSliceValidator{Values: []string{"commercial", "auth0", "gov", "fedramp"}},
+				},
+			},
+			"max_retries": {
+				MarkdownDescription: "How many times a request failing with a transient error (HTTP 429, 502, 503 or 504) is retried. Defaults to `5`, `0` disables retries.",
+				Optional:            true,
+				Type:                types.Int64Type,
+				Validators: []tfsdk.AttributeValidator{
+					int64AtLeastValidator{Min: 0},
+				},
+			},
+			"retry_max_wait": {
+				MarkdownDescription: "The longest time in seconds to wait between two retries. Defaults to `30`.",
+				Optional:            true,
+				Type:                types.Int64Type,
+				Validators: []tfsdk.AttributeValidator{
+					int64AtLeastValidator{Min: 1},
+				},
+			},
+			"request_timeout": {
+				MarkdownDescription: "The longest time in seconds a single API request may take, including its retries. By default requests are only bounded by the resource `timeouts`.",
+				Optional:            true,
+				Type:                types.Int64Type,
+				Validators: []tfsdk.AttributeValidator{
+					int64AtLeastValidator{Min: 1},
+				},
+			},
+			"credentials_file": {
+				MarkdownDescription: "Path to a YAML or JSON file of named credential profiles, each with `client_id`, `client_secret`, `url`, `auth_url`, `auth_audience` and `environment` keys. Values set in the provider block or through environment variables take precedence.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"profile": {
+				MarkdownDescription: "The profile to use from `credentials_file`. Defaults to `default`.",
 				Optional:            true,
 				Type:                types.StringType,
 			},
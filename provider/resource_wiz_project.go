@@ -2,14 +2,17 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"shell.com/terraform-provider-wiz/apiClient"
+	errorsHandler "shell.com/terraform-provider-wiz/errors"
 )
 
 type resourceWizProjectType struct{}
@@ -19,17 +22,110 @@ type wizProject struct {
 }
 
 type wizProjectTypeData struct {
-	ID                *string                    `tfsdk:"id"`
-	Name              *string                    `tfsdk:"name"`
-	CloudAccountLinks []CloudAccountLinkTypeData `tfsdk:"cloud_account_links"`
+	ID                     *string                         `tfsdk:"id"`
+	Name                   *string                         `tfsdk:"name"`
+	Description            *string                         `tfsdk:"description"`
+	BusinessUnit           *string                         `tfsdk:"business_unit"`
+	Identifiers            []string                        `tfsdk:"identifiers"`
+	Slug                   types.String                    `tfsdk:"slug"`
+	ExternalID             types.String                    `tfsdk:"external_id"`
+	UpdatedAt              types.String                    `tfsdk:"updated_at"`
+	IsFolder               *bool                           `tfsdk:"is_folder"`
+	ParentProjectID        *string                         `tfsdk:"parent_project_id"`
+	CloudOrganizationLinks []CloudOrganizationLinkTypeData `tfsdk:"cloud_organization_links"`
+	CloudAccountLinks      []CloudAccountLinkTypeData      `tfsdk:"cloud_account_links"`
+	KubernetesClusterLinks []KubernetesClusterLinkTypeData `tfsdk:"kubernetes_cluster_links"`
+	RepositoryLinks        []RepositoryLinkTypeData        `tfsdk:"repository_links"`
+	ProjectOwners          []string                        `tfsdk:"project_owners"`
+	SecurityChampions      []string                        `tfsdk:"security_champions"`
+	RiskProfile            *RiskProfileTypeData            `tfsdk:"risk_profile"`
+	DeletionMode           *string                         `tfsdk:"deletion_mode"`
+	Timeouts               *TimeoutsTypeData               `tfsdk:"timeouts"`
+}
+
+type CloudOrganizationLinkTypeData struct {
+	GUID           string                `tfsdk:"cloud_organization_guid"`
+	Environment    *string               `tfsdk:"environment"`
+	Shared         *bool                 `tfsdk:"shared"`
+	ResourceTags   []ResourceTagTypeData `tfsdk:"resource_tags"`
+	ResourceGroups []string              `tfsdk:"resource_groups"`
 }
 
 type CloudAccountLinkTypeData struct {
-	GUID        string `tfsdk:"cloud_account_guid"`
-	Environment string `tfsdk:"environment"`
-	Shared      bool   `tfsdk:"shared"`
+	GUID           string                `tfsdk:"cloud_account_guid"`
+	Environment    *string               `tfsdk:"environment"`
+	Shared         *bool                 `tfsdk:"shared"`
+	ResourceTags   []ResourceTagTypeData `tfsdk:"resource_tags"`
+	ResourceGroups []string              `tfsdk:"resource_groups"`
+}
+
+type KubernetesClusterLinkTypeData struct {
+	GUID        string   `tfsdk:"kubernetes_cluster_guid"`
+	Environment *string  `tfsdk:"environment"`
+	Namespaces  []string `tfsdk:"namespaces"`
+	Shared      *bool    `tfsdk:"shared"`
+}
+
+type RepositoryLinkTypeData struct {
+	// RepositoryID is unknown in the plan until it is resolved from the
+	// connector and full name on apply.
+	RepositoryID       types.String `tfsdk:"repository_id"`
+	VCSConnectorID     *string      `tfsdk:"vcs_connector_id"`
+	RepositoryFullName *string      `tfsdk:"repository_full_name"`
 }
 
+type ResourceTagTypeData struct {
+	Key   string `tfsdk:"key"`
+	Value string `tfsdk:"value"`
+}
+
+type RiskProfileTypeData struct {
+	BusinessImpact      *string  `tfsdk:"business_impact"`
+	HasAuthentication   *string  `tfsdk:"has_authentication"`
+	HasExposedAPI       *string  `tfsdk:"has_exposed_api"`
+	IsCustomerFacing    *string  `tfsdk:"is_customer_facing"`
+	IsInternetFacing    *string  `tfsdk:"is_internet_facing"`
+	IsActivelyDeveloped *string  `tfsdk:"is_actively_developed"`
+	IsRegulated         *string  `tfsdk:"is_regulated"`
+	SensitiveDataTypes  []string `tfsdk:"sensitive_data_types"`
+	StoresData          *string  `tfsdk:"stores_data"`
+	RegulatoryStandards []string `tfsdk:"regulatory_standards"`
+}
+
+// Allowed values for the risk profile enums of the Wiz API.
+var (
+	businessImpactValues = []string{"LBI", "MBI", "HBI"}
+	yesNoUnknownValues   = []string{"YES", "NO", "UNKNOWN"}
+	sensitiveDataValues  = []string{"CLASSIFIED", "HEALTH", "PII", "PCI", "FINANCIAL", "CUSTOMER"}
+	environmentValues    = []string{"PRODUCTION", "STAGING", "DEVELOPMENT", "TESTING", "OTHER"}
+	regulatoryValues     = []string{
+		"ISO_20000_1_2011", "ISO_22301", "ISO_27001", "ISO_27017", "ISO_27018", "ISO_27701", "ISO_9001",
+		"SOC", "FEDRAMP", "NIST_800_171", "NIST_CSF", "HIPPA_HITECH", "HITRUST", "PCI_DSS",
+		"SEC_17a_4", "SEC_REGULATION_SCI", "SOX", "GDPR",
+	}
+)
+
+const (
+	defaultBusinessImpact = "MBI"
+	defaultYesNoUnknown   = "UNKNOWN"
+	defaultEnvironment    = "PRODUCTION"
+)
+
+// wizProjectFieldNames maps the project input fields of the Wiz API onto
+// attributes whose name is not simply the field name in snake_case.
+var wizProjectFieldNames = map[string]string{
+	"cloudAccount":      "cloud_account_guid",
+	"cloudOrganization": "cloud_organization_guid",
+	"kubernetesCluster": "kubernetes_cluster_guid",
+	"repository":        "repository_id",
+}
+
+// Supported values of the deletion_mode attribute.
+const (
+	deletionModeDelete  = "delete"
+	deletionModeArchive = "archive"
+)
+
 func (t resourceWizProjectType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
 		MarkdownDescription: "Wiz Project configuration.",
@@ -51,6 +147,78 @@ func (t resourceWizProjectType) GetSchema(ctx context.Context) (tfsdk.Schema, di
 					tfsdk.UseStateForUnknown(),
 				},
 			},
+			"description": {
+				MarkdownDescription: "Description of the Project",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"business_unit": {
+				MarkdownDescription: "Business unit the Project belongs to",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"identifiers": {
+				MarkdownDescription: "Identifiers of the Project, matched against resource tags and names to suggest resources for it",
+				Optional:            true,
+				Type:                types.SetType{ElemType: types.StringType},
+			},
+			"slug": {
+				MarkdownDescription: "URL friendly name of the Project",
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					useStateUnlessChangedModifier{Attribute: "name"},
+				},
+			},
+			"external_id": {
+				MarkdownDescription: "External ID of the Project",
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					tfsdk.UseStateForUnknown(),
+				},
+			},
+			"updated_at": {
+				MarkdownDescription: "Time the Project was last updated",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"is_folder": {
+				MarkdownDescription: "Whether the Project is a folder of other projects. Folder projects link no resources themselves. Changing it re-creates the Project. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					boolDefaultModifier{Default: false},
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"parent_project_id": {
+				MarkdownDescription: "ID of the folder project containing the Project. Changing it re-creates the Project.",
+				Optional:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"cloud_organization_links": {
+				MarkdownDescription: "Cloud organizations linked to the Project: AWS organizational units, Azure management groups or GCP folders. Every account below them belongs to the Project.",
+				Optional:            true,
+				Attributes: tfsdk.ListNestedAttributes(
+					map[string]tfsdk.Attribute{
+						"cloud_organization_guid": {
+							MarkdownDescription: "GUID of the cloud organization",
+							Required:            true,
+							Type:                types.StringType,
+						},
+						"environment":     linkEnvironmentAttribute("cloud organization"),
+						"shared":          linkSharedAttribute("cloud organization"),
+						"resource_tags":   resourceTagsAttribute(),
+						"resource_groups": resourceGroupsAttribute(),
+					},
+					tfsdk.ListNestedAttributesOptions{},
+				),
+			},
 			"cloud_account_links": {
 				MarkdownDescription: "A List of cloud account ids",
 				Optional:            true,
@@ -61,24 +229,236 @@ func (t resourceWizProjectType) GetSchema(ctx context.Context) (tfsdk.Schema, di
 							Required:            true,
 							Type:                types.StringType,
 						},
-						"environment": {
-							MarkdownDescription: "DTAP environment",
+						"environment":     linkEnvironmentAttribute("cloud account"),
+						"shared":          linkSharedAttribute("cloud account"),
+						"resource_tags":   resourceTagsAttribute(),
+						"resource_groups": resourceGroupsAttribute(),
+					},
+					tfsdk.ListNestedAttributesOptions{},
+				),
+			},
+			"kubernetes_cluster_links": {
+				MarkdownDescription: "Kubernetes clusters linked to the Project",
+				Optional:            true,
+				Attributes: tfsdk.ListNestedAttributes(
+					map[string]tfsdk.Attribute{
+						"kubernetes_cluster_guid": {
+							MarkdownDescription: "GUID of the Kubernetes cluster",
+							Required:            true,
+							Type:                types.StringType,
+						},
+						"environment": linkEnvironmentAttribute("Kubernetes cluster"),
+						"namespaces": {
+							MarkdownDescription: "Only resources in these namespaces belong to the Project. Used to split a shared cluster between projects.",
+							Optional:            true,
+							Type:                types.ListType{ElemType: types.StringType},
+						},
+						"shared": linkSharedAttribute("Kubernetes cluster"),
+					},
+					tfsdk.ListNestedAttributesOptions{},
+				),
+			},
+			"repository_links": {
+				MarkdownDescription: "Code repositories linked to the Project. Each repository is given by `repository_id`, or by `vcs_connector_id` and `repository_full_name`, which the provider resolves to its ID.",
+				Optional:            true,
+				Attributes: tfsdk.ListNestedAttributes(
+					map[string]tfsdk.Attribute{
+						"repository_id": {
+							MarkdownDescription: "ID of the repository",
+							Optional:            true,
+							Computed:            true,
+							Type:                types.StringType,
+							PlanModifiers: tfsdk.AttributePlanModifiers{
+								repositoryIDModifier{},
+							},
+						},
+						"vcs_connector_id": {
+							MarkdownDescription: "ID of the version control connector scanning the repository",
 							Optional:            true,
 							Type:                types.StringType,
 						},
-						"shared": {
-							MarkdownDescription: "Optional event specification",
+						"repository_full_name": {
+							MarkdownDescription: "Full name of the repository, e.g. `my-org/my-repo`",
 							Optional:            true,
-							Type:                types.BoolType,
+							Type:                types.StringType,
 						},
 					},
 					tfsdk.ListNestedAttributesOptions{},
 				),
 			},
+			"project_owners": {
+				MarkdownDescription: "Owners of the Project, given by Wiz user ID or email address",
+				Optional:            true,
+				Type:                types.SetType{ElemType: types.StringType},
+			},
+			"security_champions": {
+				MarkdownDescription: "Security champions of the Project, given by Wiz user ID or email address",
+				Optional:            true,
+				Type:                types.SetType{ElemType: types.StringType},
+			},
+			"risk_profile": {
+				MarkdownDescription: "Risk profile of the Project. When omitted, the project is created with a business impact of `MBI` and every other answer set to `UNKNOWN`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					objectDefaultModifier{Attrs: defaultRiskProfileAttrs()},
+				},
+				Attributes: tfsdk.SingleNestedAttributes(
+					map[string]tfsdk.Attribute{
+						"business_impact": {
+							MarkdownDescription: "Business impact of the Project",
+							Optional:            true,
+							Computed:            true,
+							Type:                types.StringType,
+							Validators: []tfsdk.AttributeValidator{
+								stringInSliceValidator{Values: businessImpactValues},
+							},
+							PlanModifiers: tfsdk.AttributePlanModifiers{
+								stringDefaultModifier{Default: defaultBusinessImpact},
+							},
+						},
+						"has_authentication":    yesNoUnknownAttribute("Whether the Project requires authentication"),
+						"has_exposed_api":       yesNoUnknownAttribute("Whether the Project exposes an API"),
+						"is_customer_facing":    yesNoUnknownAttribute("Whether the Project is customer facing"),
+						"is_internet_facing":    yesNoUnknownAttribute("Whether the Project is internet facing"),
+						"is_actively_developed": yesNoUnknownAttribute("Whether the Project is actively developed"),
+						"is_regulated":          yesNoUnknownAttribute("Whether the Project is regulated"),
+						"stores_data":           yesNoUnknownAttribute("Whether the Project stores data"),
+						"sensitive_data_types": {
+							MarkdownDescription: "Types of sensitive data stored by the Project",
+							Optional:            true,
+							Type:                types.ListType{ElemType: types.StringType},
+							Validators: []tfsdk.AttributeValidator{
+								stringInSliceValidator{Values: sensitiveDataValues},
+							},
+						},
+						"regulatory_standards": {
+							MarkdownDescription: "Regulatory standards the Project must comply with",
+							Optional:            true,
+							Type:                types.ListType{ElemType: types.StringType},
+							Validators: []tfsdk.AttributeValidator{
+								stringInSliceValidator{Values: regulatoryValues},
+							},
+						},
+					},
+				),
+			},
+			"deletion_mode": {
+				MarkdownDescription: "What happens to the Project in Wiz on destroy: `delete` removes it, `archive` archives it. Defaults to `delete`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					stringInSliceValidator{Values: []string{deletionModeDelete, deletionModeArchive}},
+				},
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					stringDefaultModifier{Default: deletionModeDelete},
+				},
+			},
+			"timeouts": timeoutsAttribute(),
 		},
 	}, nil
 }
 
+// linkEnvironmentAttribute returns the schema of the environment attribute of
+// the project links to a subject such as "cloud account".
+func linkEnvironmentAttribute(subject string) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		MarkdownDescription: "DTAP environment. Defaults to `PRODUCTION`. The same " + subject + " can be linked once per environment.",
+		Optional:            true,
+		Computed:            true,
+		Type:                types.StringType,
+		Validators: []tfsdk.AttributeValidator{
+			stringInSliceValidator{Values: environmentValues},
+		},
+		PlanModifiers: tfsdk.AttributePlanModifiers{
+			stringDefaultModifier{Default: defaultEnvironment},
+		},
+	}
+}
+
+// linkSharedAttribute returns the schema of the shared attribute of the
+// project links to a subject such as "cloud account".
+func linkSharedAttribute(subject string) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		MarkdownDescription: "Whether the " + subject + " is shared with other projects. Defaults to `false`.",
+		Optional:            true,
+		Computed:            true,
+		Type:                types.BoolType,
+		PlanModifiers: tfsdk.AttributePlanModifiers{
+			boolDefaultModifier{Default: false},
+		},
+	}
+}
+
+// resourceTagsAttribute returns the schema of the resource_tags attribute of
+// the project links, which scopes a shared link to the tagged resources.
+func resourceTagsAttribute() tfsdk.Attribute {
+	return tfsdk.Attribute{
+		MarkdownDescription: "Only resources with one of these tags belong to the Project. Used to split a shared link between projects.",
+		Optional:            true,
+		Attributes: tfsdk.ListNestedAttributes(
+			map[string]tfsdk.Attribute{
+				"key": {
+					MarkdownDescription: "Tag key",
+					Required:            true,
+					Type:                types.StringType,
+				},
+				"value": {
+					MarkdownDescription: "Tag value",
+					Required:            true,
+					Type:                types.StringType,
+				},
+			},
+			tfsdk.ListNestedAttributesOptions{},
+		),
+	}
+}
+
+// resourceGroupsAttribute returns the schema of the resource_groups attribute
+// of the project links, which scopes a shared link to the named resource
+// groups.
+func resourceGroupsAttribute() tfsdk.Attribute {
+	return tfsdk.Attribute{
+		MarkdownDescription: "Only resources in one of these resource groups belong to the Project. Used to split a shared link between projects.",
+		Optional:            true,
+		Type:                types.ListType{ElemType: types.StringType},
+	}
+}
+
+// defaultRiskProfileAttrs returns the risk_profile planned when it is not
+// configured, matching the profile getRiskProfile sends for it.
+func defaultRiskProfileAttrs() map[string]attr.Value {
+	unknown := types.String{Value: defaultYesNoUnknown}
+	return map[string]attr.Value{
+		"business_impact":       types.String{Value: defaultBusinessImpact},
+		"has_authentication":    unknown,
+		"has_exposed_api":       unknown,
+		"is_customer_facing":    unknown,
+		"is_internet_facing":    unknown,
+		"is_actively_developed": unknown,
+		"is_regulated":          unknown,
+		"stores_data":           unknown,
+		"sensitive_data_types":  types.List{ElemType: types.StringType, Null: true},
+		"regulatory_standards":  types.List{ElemType: types.StringType, Null: true},
+	}
+}
+
+func yesNoUnknownAttribute(description string) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		Type:                types.StringType,
+		Validators: []tfsdk.AttributeValidator{
+			stringInSliceValidator{Values: yesNoUnknownValues},
+		},
+		PlanModifiers: tfsdk.AttributePlanModifiers{
+			stringDefaultModifier{Default: defaultYesNoUnknown},
+		},
+	}
+}
+
 func (t resourceWizProjectType) NewResource(ctx context.Context, in tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
 	provider, diags := convertProviderType(in)
 
@@ -87,28 +467,543 @@ func (t resourceWizProjectType) NewResource(ctx context.Context, in tfsdk.Provid
 	}, diags
 }
 
+func (d wizProjectTypeData) getOrganizationLinks(ctx context.Context) []apiClient.CloudOrganizationLink {
+	// an empty list rather than nil, so that removing every link clears them
+	cloudOrganizationLinks := []apiClient.CloudOrganizationLink{}
+
+	for _, ol := range d.CloudOrganizationLinks {
+		ol = ol.withDefaults()
+		cloudOrganizationLinks = append(cloudOrganizationLinks, apiClient.CloudOrganizationLink{
+			CloudOrganization: ol.GUID,
+			Environment:       *ol.Environment,
+			Shared:            *ol.Shared,
+			ResourceTags:      getResourceTags(ol.ResourceTags),
+			ResourceGroups:    listOrEmpty(ol.ResourceGroups),
+		})
+	}
+
+	return cloudOrganizationLinks
+}
+
+// setOrganizationLinks replaces the cloud organization links with those read
+// from the API, in the order described by orderLinks.
+func (d *wizProjectTypeData) setOrganizationLinks(ctx context.Context, cloudOrganizationLinks []apiClient.CloudOrganizationLinkDetails) {
+	d.CloudOrganizationLinks = orderLinks(d.CloudOrganizationLinks, cloudOrganizationLinks,
+		func(ol CloudOrganizationLinkTypeData) (string, string) {
+			return ol.GUID, *ol.withDefaults().Environment
+		},
+		func(link apiClient.CloudOrganizationLinkDetails) (string, string) {
+			return link.CloudOrganization.ID, environmentOrDefault(link.Environment)
+		},
+		newCloudOrganizationLinkTypeData,
+	)
+}
+
+func (ol CloudOrganizationLinkTypeData) withDefaults() CloudOrganizationLinkTypeData {
+	ol.Environment, ol.Shared = linkDefaults(ol.Environment, ol.Shared)
+	return ol
+}
+
+// newCloudOrganizationLinkTypeData converts a link read from the API like
+// newCloudAccountLinkTypeData.
+func newCloudOrganizationLinkTypeData(link apiClient.CloudOrganizationLinkDetails, previous CloudOrganizationLinkTypeData) CloudOrganizationLinkTypeData {
+	environment := environmentOrDefault(link.Environment)
+	shared := link.Shared
+	return CloudOrganizationLinkTypeData{
+		GUID:           link.CloudOrganization.ID,
+		Environment:    &environment,
+		Shared:         &shared,
+		ResourceTags:   setResourceTags(link.ResourceTags, previous.ResourceTags),
+		ResourceGroups: listOrPrevious(link.ResourceGroups, previous.ResourceGroups),
+	}
+}
+
 func (d wizProjectTypeData) getAccountLinks(ctx context.Context) []apiClient.CloudAccountLink {
-	var cloudAccountLinks []apiClient.CloudAccountLink
+	// an empty list rather than nil, so that removing every link clears them
+	cloudAccountLinks := []apiClient.CloudAccountLink{}
 
 	for _, cl := range d.CloudAccountLinks {
+		cl = cl.withDefaults()
 		cloudAccountLinks = append(cloudAccountLinks, apiClient.CloudAccountLink{
-			CloudAccount: cl.GUID,
-			Environment:  cl.Environment,
-			Shared:       cl.Shared,
+			CloudAccount:   cl.GUID,
+			Environment:    *cl.Environment,
+			Shared:         *cl.Shared,
+			ResourceTags:   getResourceTags(cl.ResourceTags),
+			ResourceGroups: listOrEmpty(cl.ResourceGroups),
 		})
 	}
 
 	return cloudAccountLinks
 }
 
-func (d wizProjectTypeData) setAccountLinks(ctx context.Context, cloudAccountLinks apiClient.Subscriptions) {
-	for _, cl := range cloudAccountLinks {
-		d.CloudAccountLinks = append(d.CloudAccountLinks, CloudAccountLinkTypeData{
-			GUID:        cl.SubscriptionID,
-			Environment: cl.Environments[0],
-			Shared:      cl.SharedAccount,
+// setAccountLinks replaces the cloud account links with those read from the
+// API, in the order described by orderLinks.
+func (d *wizProjectTypeData) setAccountLinks(ctx context.Context, cloudAccountLinks []apiClient.CloudAccountLinkDetails) {
+	d.CloudAccountLinks = orderLinks(d.CloudAccountLinks, cloudAccountLinks,
+		func(cl CloudAccountLinkTypeData) (string, string) {
+			return cl.GUID, *cl.withDefaults().Environment
+		},
+		func(link apiClient.CloudAccountLinkDetails) (string, string) {
+			return link.CloudAccount.ID, environmentOrDefault(link.Environment)
+		},
+		newCloudAccountLinkTypeData,
+	)
+}
+
+// orderLinks converts the links read from the API, keeping the order the links
+// have in the current state or plan. Current links are matched on their ID and
+// environment first and on their ID alone second, so that a changed
+// environment shows as an in-place diff; links added outside of Terraform are
+// appended in API order. convert receives the matched current link, or the
+// zero value for appended links.
+func orderLinks[L any, D any](current []D, links []L, currentKey func(D) (string, string), linkKey func(L) (string, string), convert func(L, D) D) []D {
+	remaining := make([]*L, len(links))
+	for i := range links {
+		remaining[i] = &links[i]
+	}
+
+	take := func(id string, environment *string) *L {
+		for i, link := range remaining {
+			if link == nil {
+				continue
+			}
+			linkID, linkEnvironment := linkKey(*link)
+			if linkID == id && (environment == nil || linkEnvironment == *environment) {
+				remaining[i] = nil
+				return link
+			}
+		}
+		return nil
+	}
+
+	var result []D
+	for _, c := range current {
+		id, environment := currentKey(c)
+		link := take(id, &environment)
+		if link == nil {
+			link = take(id, nil)
+		}
+		if link != nil {
+			result = append(result, convert(*link, c))
+		}
+	}
+	var zero D
+	for _, link := range remaining {
+		if link != nil {
+			result = append(result, convert(*link, zero))
+		}
+	}
+
+	// keep an empty list as it was configured, null and empty are not equal
+	if result == nil && current != nil {
+		result = []D{}
+	}
+	return result
+}
+
+func (d wizProjectTypeData) getKubernetesClusterLinks(ctx context.Context) []apiClient.KubernetesClusterLink {
+	// an empty list rather than nil, so that removing every link clears them
+	kubernetesClusterLinks := []apiClient.KubernetesClusterLink{}
+
+	for _, kl := range d.KubernetesClusterLinks {
+		kl = kl.withDefaults()
+		kubernetesClusterLinks = append(kubernetesClusterLinks, apiClient.KubernetesClusterLink{
+			KubernetesCluster: kl.GUID,
+			Environment:       *kl.Environment,
+			Namespaces:        listOrEmpty(kl.Namespaces),
+			Shared:            *kl.Shared,
 		})
 	}
+
+	return kubernetesClusterLinks
+}
+
+// setKubernetesClusterLinks replaces the Kubernetes cluster links with those
+// read from the API, in the order described by orderLinks.
+func (d *wizProjectTypeData) setKubernetesClusterLinks(ctx context.Context, kubernetesClusterLinks []apiClient.KubernetesClusterLinkDetails) {
+	d.KubernetesClusterLinks = orderLinks(d.KubernetesClusterLinks, kubernetesClusterLinks,
+		func(kl KubernetesClusterLinkTypeData) (string, string) {
+			return kl.GUID, *kl.withDefaults().Environment
+		},
+		func(link apiClient.KubernetesClusterLinkDetails) (string, string) {
+			return link.KubernetesCluster.ID, environmentOrDefault(link.Environment)
+		},
+		newKubernetesClusterLinkTypeData,
+	)
+}
+
+func (kl KubernetesClusterLinkTypeData) withDefaults() KubernetesClusterLinkTypeData {
+	kl.Environment, kl.Shared = linkDefaults(kl.Environment, kl.Shared)
+	return kl
+}
+
+// newKubernetesClusterLinkTypeData converts a link read from the API, keeping
+// the null or empty namespaces of the previous value of the link where the
+// API returned none.
+func newKubernetesClusterLinkTypeData(link apiClient.KubernetesClusterLinkDetails, previous KubernetesClusterLinkTypeData) KubernetesClusterLinkTypeData {
+	environment := environmentOrDefault(link.Environment)
+	shared := link.Shared
+	return KubernetesClusterLinkTypeData{
+		GUID:        link.KubernetesCluster.ID,
+		Environment: &environment,
+		Namespaces:  listOrPrevious(link.Namespaces, previous.Namespaces),
+		Shared:      &shared,
+	}
+}
+
+// resolveRepositoryLinks fills in the ID of the repository links given by
+// connector and full name, reporting the links that cannot be resolved.
+func (r wizProject) resolveRepositoryLinks(ctx context.Context, data *wizProjectTypeData, diags *diag.Diagnostics) {
+	for i, rl := range data.RepositoryLinks {
+		if (!rl.RepositoryID.Null && !rl.RepositoryID.Unknown) || rl.VCSConnectorID == nil || rl.RepositoryFullName == nil {
+			continue
+		}
+
+		id, err := r.provider.wizClient.FindRepositoryID(ctx, *rl.VCSConnectorID, *rl.RepositoryFullName)
+		if err != nil {
+			diags.AddAttributeError(
+				tftypes.NewAttributePath().WithAttributeName("repository_links").WithElementKeyInt(i).WithAttributeName("repository_full_name"),
+				"Resolving repository failed.",
+				fmt.Sprintf("Unable to resolve repository %q, got error: %s", *rl.RepositoryFullName, err))
+			continue
+		}
+		data.RepositoryLinks[i].RepositoryID = types.String{Value: id}
+	}
+}
+
+func (d wizProjectTypeData) getRepositoryLinks(ctx context.Context) []apiClient.RepositoryLink {
+	// an empty list rather than nil, so that removing every link clears them
+	repositoryLinks := []apiClient.RepositoryLink{}
+
+	for _, rl := range d.RepositoryLinks {
+		repositoryLinks = append(repositoryLinks, apiClient.RepositoryLink{
+			Repository: rl.RepositoryID.Value,
+		})
+	}
+
+	return repositoryLinks
+}
+
+// setRepositoryLinks replaces the repository links with those read from the
+// API, in the order described by orderLinks. Repositories have no
+// environment, so they are matched on their ID only.
+func (d *wizProjectTypeData) setRepositoryLinks(ctx context.Context, repositoryLinks []apiClient.RepositoryLinkDetails) {
+	d.RepositoryLinks = orderLinks(d.RepositoryLinks, repositoryLinks,
+		func(rl RepositoryLinkTypeData) (string, string) {
+			return rl.RepositoryID.Value, ""
+		},
+		func(link apiClient.RepositoryLinkDetails) (string, string) {
+			return link.Repository.ID, ""
+		},
+		newRepositoryLinkTypeData,
+	)
+}
+
+// newRepositoryLinkTypeData converts a link read from the API, keeping the
+// connector and full name the link was configured with, as the API only
+// returns the repository ID.
+func newRepositoryLinkTypeData(link apiClient.RepositoryLinkDetails, previous RepositoryLinkTypeData) RepositoryLinkTypeData {
+	return RepositoryLinkTypeData{
+		RepositoryID:       types.String{Value: link.Repository.ID},
+		VCSConnectorID:     previous.VCSConnectorID,
+		RepositoryFullName: previous.RepositoryFullName,
+	}
+}
+
+// resolveUsers returns the user IDs of the users of attribute, given by ID or
+// email address, reporting the email addresses no Wiz user has.
+func (r wizProject) resolveUsers(ctx context.Context, attribute string, users []string, diags *diag.Diagnostics) []string {
+	// an empty list rather than nil, so that removing every user clears them
+	ids := []string{}
+
+	for _, user := range users {
+		if !strings.Contains(user, "@") {
+			ids = append(ids, user)
+			continue
+		}
+
+		found, err := r.provider.wizClient.FindUserByEmail(ctx, user)
+		if err != nil {
+			diags.AddAttributeError(tftypes.NewAttributePath().WithAttributeName(attribute),
+				"Resolving user failed.", fmt.Sprintf("Unable to resolve user %q, got error: %s", user, err))
+			continue
+		}
+		if found == nil {
+			diags.AddAttributeError(tftypes.NewAttributePath().WithAttributeName(attribute),
+				"Unknown user.", fmt.Sprintf("No Wiz user has the email address %q.", user))
+			continue
+		}
+		ids = append(ids, found.ID)
+	}
+
+	return ids
+}
+
+// usersOrPrevious converts the users read from the API, naming each user by
+// ID or by email address as it was named in the previous value of the
+// attribute, and by ID when it was not named there.
+func usersOrPrevious(users []apiClient.User, previous []string) []string {
+	if len(users) == 0 && len(previous) == 0 {
+		return previous
+	}
+
+	result := []string{}
+	for _, user := range users {
+		name := user.ID
+		for _, p := range previous {
+			if p == user.ID || (user.Email != "" && strings.EqualFold(p, user.Email)) {
+				name = p
+				break
+			}
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+// readProjectProperties sets the computed properties of the project that only
+// the graph knows. Unless refresh is set, only the properties planned unknown
+// are set, as any other planned value, null included, must be kept. New
+// projects may take a moment to reach the graph, so properties it does not
+// have yet are left null until the next refresh.
+//
+// The graph search is costly, so a refresh only runs it while the slug,
+// which every project in the graph has, is not known yet, e.g. after an
+// import. The properties are kept as they are otherwise, and updated_at is
+// read again when an update plans it unknown.
+func (r wizProject) readProjectProperties(ctx context.Context, data *wizProjectTypeData, refresh bool, diags *diag.Diagnostics) {
+	if !data.Slug.Unknown && !data.ExternalID.Unknown && !data.UpdatedAt.Unknown &&
+		!(refresh && data.Slug.Null) {
+		return
+	}
+
+	entity, err := r.provider.wizClient.GetProjectEntity(ctx, *data.ID)
+	if err != nil {
+		diags.AddWarning("Getting Wiz Project properties failed.",
+			fmt.Sprintf("Unable to get the slug, external ID and update time of the Wiz Project, got error: %s", err))
+	}
+
+	set := func(target *types.String, value string) {
+		switch {
+		case !refresh && !target.Unknown:
+			// keep the planned value
+		case entity != nil && value != "":
+			*target = types.String{Value: value}
+		case entity != nil || target.Unknown:
+			*target = types.String{Null: true}
+		}
+	}
+	var properties apiClient.Properties
+	if entity != nil {
+		properties = entity.Properties
+	}
+	set(&data.Slug, properties.Slug)
+	set(&data.ExternalID, properties.ExternalID)
+	set(&data.UpdatedAt, properties.UpdatedAt)
+}
+
+// setLinkDefaults fills in the defaults of unset link attributes, as planned
+// by their plan modifiers.
+func (d *wizProjectTypeData) setLinkDefaults() {
+	for i, ol := range d.CloudOrganizationLinks {
+		d.CloudOrganizationLinks[i] = ol.withDefaults()
+	}
+	for i, cl := range d.CloudAccountLinks {
+		d.CloudAccountLinks[i] = cl.withDefaults()
+	}
+	for i, kl := range d.KubernetesClusterLinks {
+		d.KubernetesClusterLinks[i] = kl.withDefaults()
+	}
+}
+
+func (cl CloudAccountLinkTypeData) withDefaults() CloudAccountLinkTypeData {
+	cl.Environment, cl.Shared = linkDefaults(cl.Environment, cl.Shared)
+	return cl
+}
+
+// linkDefaults returns the environment and shared attributes of a link, or
+// their defaults when unset.
+func linkDefaults(environment *string, shared *bool) (*string, *bool) {
+	if environment == nil {
+		e := defaultEnvironment
+		environment = &e
+	}
+	if shared == nil {
+		s := false
+		shared = &s
+	}
+	return environment, shared
+}
+
+// newCloudAccountLinkTypeData converts a link read from the API, keeping the
+// null or empty lists of the previous value of the link where the API
+// returned no tags or resource groups.
+func newCloudAccountLinkTypeData(link apiClient.CloudAccountLinkDetails, previous CloudAccountLinkTypeData) CloudAccountLinkTypeData {
+	environment := environmentOrDefault(link.Environment)
+	shared := link.Shared
+	return CloudAccountLinkTypeData{
+		GUID:           link.CloudAccount.ID,
+		Environment:    &environment,
+		Shared:         &shared,
+		ResourceTags:   setResourceTags(link.ResourceTags, previous.ResourceTags),
+		ResourceGroups: listOrPrevious(link.ResourceGroups, previous.ResourceGroups),
+	}
+}
+
+func getResourceTags(tags []ResourceTagTypeData) []apiClient.ResourceTag {
+	resourceTags := []apiClient.ResourceTag{}
+	for _, tag := range tags {
+		resourceTags = append(resourceTags, apiClient.ResourceTag{
+			Key:   tag.Key,
+			Value: tag.Value,
+		})
+	}
+	return resourceTags
+}
+
+// setResourceTags returns the resource tags read from the API, following the
+// same rule for null and empty lists as listOrPrevious.
+func setResourceTags(tags []apiClient.ResourceTag, previous []ResourceTagTypeData) []ResourceTagTypeData {
+	if len(tags) == 0 && len(previous) == 0 {
+		return previous
+	}
+	resourceTags := []ResourceTagTypeData{}
+	for _, tag := range tags {
+		resourceTags = append(resourceTags, ResourceTagTypeData{
+			Key:   tag.Key,
+			Value: tag.Value,
+		})
+	}
+	return resourceTags
+}
+
+func environmentOrDefault(environment string) string {
+	if environment == "" {
+		return defaultEnvironment
+	}
+	return environment
+}
+
+func (d wizProjectTypeData) getRiskProfile(ctx context.Context) apiClient.RiskProfile {
+	profile := apiClient.RiskProfile{
+		BusinessImpact:      defaultBusinessImpact,
+		HasAuthentication:   defaultYesNoUnknown,
+		HasExposedAPI:       defaultYesNoUnknown,
+		IsCustomerFacing:    defaultYesNoUnknown,
+		IsInternetFacing:    defaultYesNoUnknown,
+		IsActivelyDeveloped: defaultYesNoUnknown,
+		IsRegulated:         defaultYesNoUnknown,
+		SensitiveDataTypes:  []string{},
+		StoresData:          defaultYesNoUnknown,
+		RegulatoryStandards: []string{},
+	}
+
+	rp := d.RiskProfile
+	if rp == nil {
+		return profile
+	}
+
+	setIfNotNil(&profile.BusinessImpact, rp.BusinessImpact)
+	setIfNotNil(&profile.HasAuthentication, rp.HasAuthentication)
+	setIfNotNil(&profile.HasExposedAPI, rp.HasExposedAPI)
+	setIfNotNil(&profile.IsCustomerFacing, rp.IsCustomerFacing)
+	setIfNotNil(&profile.IsInternetFacing, rp.IsInternetFacing)
+	setIfNotNil(&profile.IsActivelyDeveloped, rp.IsActivelyDeveloped)
+	setIfNotNil(&profile.IsRegulated, rp.IsRegulated)
+	setIfNotNil(&profile.StoresData, rp.StoresData)
+	if rp.SensitiveDataTypes != nil {
+		profile.SensitiveDataTypes = rp.SensitiveDataTypes
+	}
+	if rp.RegulatoryStandards != nil {
+		profile.RegulatoryStandards = rp.RegulatoryStandards
+	}
+
+	return profile
+}
+
+func (d *wizProjectTypeData) setRiskProfile(ctx context.Context, profile apiClient.RiskProfile) {
+	previous := d.RiskProfile
+	if previous == nil {
+		previous = &RiskProfileTypeData{}
+	}
+
+	d.RiskProfile = &RiskProfileTypeData{
+		BusinessImpact:      stringOrDefault(profile.BusinessImpact, defaultBusinessImpact),
+		HasAuthentication:   stringOrDefault(profile.HasAuthentication, defaultYesNoUnknown),
+		HasExposedAPI:       stringOrDefault(profile.HasExposedAPI, defaultYesNoUnknown),
+		IsCustomerFacing:    stringOrDefault(profile.IsCustomerFacing, defaultYesNoUnknown),
+		IsInternetFacing:    stringOrDefault(profile.IsInternetFacing, defaultYesNoUnknown),
+		IsActivelyDeveloped: stringOrDefault(profile.IsActivelyDeveloped, defaultYesNoUnknown),
+		IsRegulated:         stringOrDefault(profile.IsRegulated, defaultYesNoUnknown),
+		SensitiveDataTypes:  listOrPrevious(profile.SensitiveDataTypes, previous.SensitiveDataTypes),
+		StoresData:          stringOrDefault(profile.StoresData, defaultYesNoUnknown),
+		RegulatoryStandards: listOrPrevious(profile.RegulatoryStandards, previous.RegulatoryStandards),
+	}
+}
+
+func (d *wizProjectTypeData) setFolderDefault() {
+	if d.IsFolder == nil {
+		isFolder := false
+		d.IsFolder = &isFolder
+	}
+}
+
+func (d *wizProjectTypeData) setDeletionModeDefault() {
+	if d.DeletionMode == nil {
+		mode := deletionModeDelete
+		d.DeletionMode = &mode
+	}
+}
+
+func setIfNotNil(target *string, value *string) {
+	if value != nil {
+		*target = *value
+	}
+}
+
+// listOrPrevious returns value unless it is empty. An empty value keeps a
+// null or empty previous list as it was configured, so that neither produces
+// a diff, and otherwise is returned as an empty list.
+func listOrPrevious(value []string, previous []string) []string {
+	if len(value) == 0 && len(previous) == 0 {
+		return previous
+	}
+	if len(value) == 0 {
+		return []string{}
+	}
+	return value
+}
+
+// listOrEmpty returns value, or an empty list when it is nil so that the API
+// clears the field rather than ignoring it.
+func listOrEmpty(value []string) []string {
+	if value == nil {
+		return []string{}
+	}
+	return value
+}
+
+// stringOrPrevious returns value, or the previous value when value is empty
+// and the previous value is null or empty.
+func stringOrPrevious(value string, previous *string) *string {
+	if value == "" && (previous == nil || *previous == "") {
+		return previous
+	}
+	return &value
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func stringOrDefault(value string, def string) *string {
+	if value == "" {
+		return &def
+	}
+	return &value
 }
 
 func (r wizProject) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
@@ -123,35 +1018,53 @@ func (r wizProject) Create(ctx context.Context, req tfsdk.CreateResourceRequest,
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.create())
+	defer cancel()
+
+	r.resolveRepositoryLinks(ctx, &data, &resp.Diagnostics)
+	projectOwners := r.resolveUsers(ctx, "project_owners", data.ProjectOwners, &resp.Diagnostics)
+	securityChampions := r.resolveUsers(ctx, "security_champions", data.SecurityChampions, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	client_resp, err := r.provider.wizClient.CreateWizProject(ctx, apiClient.CreateProjectRequest{
 		Input: apiClient.CreateProjectInput{
-			Name: data.Name,
-			// TODO we are passing context but it isn't being used, either use it os lose it
-			CloudAccountLinks: data.getAccountLinks(ctx),
-			// TODO remove hard-coded risk profile until we know what we need to do
-			RiskProfile: apiClient.RiskProfile{
-				BusinessImpact:      "MBI",
-				HasAuthentication:   "UNKNOWN",
-				HasExposedAPI:       "YES",
-				IsCustomerFacing:    "NO",
-				IsInternetFacing:    "YES",
-				IsActivelyDeveloped: "UNKNOWN",
-				IsRegulated:         "YES",
-				SensitiveDataTypes:  []string{"CUSTOMER", "FINANCIAL"},
-				StoresData:          "YES",
-				RegulatoryStandards: []string{"SOC"},
-			},
+			Name:                   data.Name,
+			Description:            valueOrEmpty(data.Description),
+			BusinessUnit:           valueOrEmpty(data.BusinessUnit),
+			Identifiers:            listOrEmpty(data.Identifiers),
+			IsFolder:               data.IsFolder != nil && *data.IsFolder,
+			ParentProjectID:        data.ParentProjectID,
+			CloudOrganizationLinks: data.getOrganizationLinks(ctx),
+			CloudAccountLinks:      data.getAccountLinks(ctx),
+			KubernetesClusterLinks: data.getKubernetesClusterLinks(ctx),
+			RepositoryLinks:        data.getRepositoryLinks(ctx),
+			ProjectOwners:          projectOwners,
+			SecurityChampions:      securityChampions,
+			RiskProfile:            data.getRiskProfile(ctx),
 		},
 	},
 	)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Creating Wiz Project Failed failed.",
-			fmt.Sprintf("Unable to create Wiz Project, got error: %s", err))
+		schema, _ := resourceWizProjectType{}.GetSchema(ctx)
+		addAPIErrorDiagnostics(&resp.Diagnostics, schema, wizProjectFieldNames,
+			"Creating Wiz Project Failed failed.", "Unable to create Wiz Project", err)
 		return
 	}
 
 	data.ID = client_resp.CreateProject.Project.ID
+	data.setLinkDefaults()
+	data.setRiskProfile(ctx, data.getRiskProfile(ctx))
+	data.setDeletionModeDefault()
+	data.setFolderDefault()
+	// data was read from the config, where the computed properties are null,
+	// while they are planned unknown
+	data.Slug = types.String{Unknown: true}
+	data.ExternalID = types.String{Unknown: true}
+	data.UpdatedAt = types.String{Unknown: true}
+	r.readProjectProperties(ctx, &data, false, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -167,49 +1080,89 @@ func (r wizProject) Update(ctx context.Context, req tfsdk.UpdateResourceRequest,
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts.update())
+	defer cancel()
+
+	r.resolveRepositoryLinks(ctx, &data, &resp.Diagnostics)
+	projectOwners := r.resolveUsers(ctx, "project_owners", data.ProjectOwners, &resp.Diagnostics)
+	securityChampions := r.resolveUsers(ctx, "security_champions", data.SecurityChampions, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	_, err := r.provider.wizClient.UpdateWizProject(ctx, apiClient.UpdateProjectRequest{
 		Input: apiClient.Input{
 			ID: *data.ID,
 			Override: apiClient.Override{
-				Name: data.Name,
-				// TODO we are passing context but it isn't being used, either use it os lose it
-				CloudAccountLinks: data.getAccountLinks(ctx),
-				// TODO remove hard-coded risk profile until we know what we need to do
-				RiskProfile: apiClient.RiskProfile{
-					BusinessImpact:      "MBI",
-					HasAuthentication:   "UNKNOWN",
-					HasExposedAPI:       "YES",
-					IsCustomerFacing:    "NO",
-					IsInternetFacing:    "YES",
-					IsActivelyDeveloped: "UNKNOWN",
-					IsRegulated:         "YES",
-					SensitiveDataTypes:  []string{"CUSTOMER", "FINANCIAL"},
-					StoresData:          "YES",
-					RegulatoryStandards: []string{"SOC"},
-				},
+				Name:                   data.Name,
+				Description:            valueOrEmpty(data.Description),
+				BusinessUnit:           valueOrEmpty(data.BusinessUnit),
+				Identifiers:            listOrEmpty(data.Identifiers),
+				CloudOrganizationLinks: data.getOrganizationLinks(ctx),
+				CloudAccountLinks:      data.getAccountLinks(ctx),
+				KubernetesClusterLinks: data.getKubernetesClusterLinks(ctx),
+				RepositoryLinks:        data.getRepositoryLinks(ctx),
+				ProjectOwners:          projectOwners,
+				SecurityChampions:      securityChampions,
+				RiskProfile:            data.getRiskProfile(ctx),
 			},
 		},
 	})
 
 	if err != nil {
-		resp.Diagnostics.AddError("Updating Wiz Project Failed failed.",
-			fmt.Sprintf("Unable to update Wiz Project, got error: %s", err))
+		schema, _ := resourceWizProjectType{}.GetSchema(ctx)
+		addAPIErrorDiagnostics(&resp.Diagnostics, schema, wizProjectFieldNames,
+			"Updating Wiz Project Failed failed.", "Unable to update Wiz Project", err)
 		return
 	}
 
+	data.setLinkDefaults()
+	data.setRiskProfile(ctx, data.getRiskProfile(ctx))
+	r.readProjectProperties(ctx, &data, false, &resp.Diagnostics)
+
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r wizProject) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
-	var data resourceWizProjectType
+	var data wizProjectTypeData
 	diags := req.State.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts.delete())
+	defer cancel()
+
+	var err error
+	if data.DeletionMode != nil && *data.DeletionMode == deletionModeArchive {
+		_, err = r.provider.wizClient.ArchiveWizProject(ctx, apiClient.ArchiveProjectRequest{
+			Input: apiClient.ArchiveProjectInput{
+				ID:    *data.ID,
+				Patch: apiClient.ArchivePatch{Archived: true},
+			},
+		})
+	} else {
+		_, err = r.provider.wizClient.DeleteWizProject(ctx, apiClient.DeleteProjectRequest{
+			Input: apiClient.DeleteProjectInput{
+				ID: *data.ID,
+			},
+		})
+	}
+
+	if err != nil {
+		// the project is already gone, so there is nothing left to destroy
+		if errorsHandler.NotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError("Deleting Wiz Project failed.",
+			fmt.Sprintf("Unable to delete Wiz Project, got error: %s", err))
+		return
+	}
 }
+
 func (r wizProject) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
 	var data wizProjectTypeData
 	diags := req.State.Get(ctx, &data)
@@ -219,46 +1172,198 @@ func (r wizProject) Read(ctx context.Context, req tfsdk.ReadResourceRequest, res
 		return
 	}
 
-	client_resp, err := r.provider.wizClient.GetWizProject(ctx, apiClient.GetProjectRequest{
-		First:           1,
-		ProjectID:       data.ID,
-		FetchTotalCount: true,
-		Quick:           false,
-		Query: apiClient.Query{
-			Type: []string{
-				"PROJECT",
-			},
-		},
-	})
+	ctx, cancel := withTimeout(ctx, data.Timeouts.read())
+	defer cancel()
+
+	if data.ID == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	client_resp, err := r.provider.wizClient.GetProjectByID(ctx, *data.ID)
 
 	if err != nil {
+		// the project was removed outside of Terraform, plan to re-create it
+		if errorsHandler.NotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Getting Wiz project failed.",
 			fmt.Sprintf("Unable to get Wiz Project, got error: %s", err))
 		return
 	}
 
-	entities := client_resp.GraphSearch.Nodes[0].Entities[0]
+	project := client_resp.Project
+	if project == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.ID = &project.ID
+	data.Name = &project.Name
+	data.Description = stringOrPrevious(project.Description, data.Description)
+	data.BusinessUnit = stringOrPrevious(project.BusinessUnit, data.BusinessUnit)
+	data.Identifiers = listOrPrevious(project.Identifiers, data.Identifiers)
+	data.IsFolder = &project.IsFolder
+	if project.ParentProject != nil {
+		data.ParentProjectID = project.ParentProject.ID
+	} else {
+		data.ParentProjectID = nil
+	}
+	data.setOrganizationLinks(ctx, project.CloudOrganizationLinks)
+	data.setAccountLinks(ctx, project.CloudAccountLinks)
+	data.setKubernetesClusterLinks(ctx, project.KubernetesClustersLinks)
+	data.setRepositoryLinks(ctx, project.RepositoryLinks)
+	data.ProjectOwners = usersOrPrevious(project.ProjectOwners, data.ProjectOwners)
+	data.SecurityChampions = usersOrPrevious(project.SecurityChampions, data.SecurityChampions)
+	r.readProjectProperties(ctx, &data, true, &resp.Diagnostics)
+	data.setRiskProfile(ctx, project.RiskProfile)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+}
+
+// repositoryLinkConfig is a repository link as configured, before any of its
+// values is known.
+type repositoryLinkConfig struct {
+	RepositoryID       types.String `tfsdk:"repository_id"`
+	VCSConnectorID     types.String `tfsdk:"vcs_connector_id"`
+	RepositoryFullName types.String `tfsdk:"repository_full_name"`
+}
 
-	var subscriptions apiClient.Subscriptions
-	var val []byte = []byte(client_resp.GraphSearch.Nodes[0].Entities[0].Properties.Subscriptions)
+// linkAttributes are the attributes linking resources to a project, which
+// folder projects cannot have.
+var linkAttributes = []string{
+	"cloud_organization_links",
+	"cloud_account_links",
+	"kubernetes_cluster_links",
+	"repository_links",
+}
 
-	marshal_err := json.Unmarshal(val, &subscriptions)
+func (r wizProject) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	validateFolderLinks(ctx, req.Config, &resp.Diagnostics)
+	validateRepositoryLinks(ctx, req.Config, &resp.Diagnostics)
+}
 
-	if marshal_err != nil {
-		resp.Diagnostics.AddError("Unmarshalling subscription data failed.",
-			fmt.Sprintf("Unable to seraialise stringified subscriptions, got error: %s", err))
-		panic(err)
+// validateFolderLinks checks that only leaf projects link resources, folder
+// projects get theirs from their child projects.
+func validateFolderLinks(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var isFolder types.Bool
+	d := config.GetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("is_folder"), &isFolder)
+	diags.Append(d...)
+	if d.HasError() || isFolder.Null || isFolder.Unknown || !isFolder.Value {
+		return
 	}
 
-	data.Name = entities.Name
-	data.ID = entities.ID
-	data.setAccountLinks(ctx, subscriptions)
+	for _, attribute := range linkAttributes {
+		path := tftypes.NewAttributePath().WithAttributeName(attribute)
+		var links types.List
+		d := config.GetAttribute(ctx, path, &links)
+		diags.Append(d...)
+		if d.HasError() || links.Null || links.Unknown || len(links.Elems) == 0 {
+			continue
+		}
+		diags.AddAttributeError(path, "Invalid folder project",
+			fmt.Sprintf("Folder projects cannot have %s, set them on their child projects instead.", attribute))
+	}
+}
 
-	diags = resp.State.Set(ctx, &data)
-	resp.Diagnostics.Append(diags...)
+// validateRepositoryLinks checks that every repository link gives either its
+// repository ID, or the connector and full name to resolve it from.
+func validateRepositoryLinks(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	linksPath := tftypes.NewAttributePath().WithAttributeName("repository_links")
+
+	var links types.List
+	d := config.GetAttribute(ctx, linksPath, &links)
+	diags.Append(d...)
+	if d.HasError() || links.Null || links.Unknown {
+		return
+	}
+
+	for i, elem := range links.Elems {
+		// links set from other resources may not be known yet
+		var link types.Object
+		d := tfsdk.ValueAs(ctx, elem, &link)
+		diags.Append(d...)
+		if d.HasError() || link.Null || link.Unknown {
+			continue
+		}
 
+		var rl repositoryLinkConfig
+		d = tfsdk.ValueAs(ctx, link, &rl)
+		diags.Append(d...)
+		if d.HasError() {
+			continue
+		}
+
+		byID := !rl.RepositoryID.Null
+		byName := !rl.VCSConnectorID.Null || !rl.RepositoryFullName.Null
+		switch {
+		case byID && byName:
+			diags.AddAttributeError(linksPath.WithElementKeyInt(i), "Invalid repository link",
+				"Set either repository_id, or vcs_connector_id and repository_full_name, not both.")
+		case !byID && (rl.VCSConnectorID.Null || rl.RepositoryFullName.Null):
+			diags.AddAttributeError(linksPath.WithElementKeyInt(i), "Invalid repository link",
+				"Set either repository_id, or both vcs_connector_id and repository_full_name.")
+		}
+	}
 }
 
 func (r wizProject) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
-	tfsdk.ResourceImportStatePassthroughID(ctx, tftypes.NewAttributePath().WithAttributeName("key"), req, resp)
+	id := req.ID
+
+	// besides a plain project ID, "name:<project name>" and "slug:<slug>" are
+	// accepted and resolved to the project ID through a graph search.
+	if field, value, found := strings.Cut(req.ID, ":"); found && (field == "name" || field == "slug") {
+		projectID, err := r.lookupProjectID(ctx, field, value)
+		if err != nil {
+			resp.Diagnostics.AddError("Importing Wiz Project failed.",
+				fmt.Sprintf("Unable to resolve Wiz Project from import ID %q, got error: %s", req.ID, err))
+			return
+		}
+		id = projectID
+	}
+
+	diags := resp.State.SetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("id"), id)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.SetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("deletion_mode"), deletionModeDelete)
+	resp.Diagnostics.Append(diags...)
+}
+
+// lookupProjectID finds the ID of the single project whose field (name or
+// slug) equals value.
+func (r wizProject) lookupProjectID(ctx context.Context, field string, value string) (string, error) {
+	// any match beyond the first makes the lookup ambiguous
+	entities, err := r.provider.wizClient.SearchWizProjects(ctx, apiClient.Query{
+		Type: []string{
+			"PROJECT",
+		},
+		Where: map[string]interface{}{
+			field: map[string]interface{}{
+				"EQUALS": []string{value},
+			},
+		},
+	}, apiClient.PaginateOptions{MaxResults: 1})
+
+	if err != nil && !errors.Is(err, apiClient.ErrMaxResultsReached) {
+		return "", err
+	}
+
+	var ids []string
+	for _, entity := range entities {
+		if entity.ID != nil {
+			ids = append(ids, *entity.ID)
+		}
+	}
+
+	switch {
+	case errors.Is(err, apiClient.ErrMaxResultsReached):
+		return "", fmt.Errorf("more than one project found with %s %q", field, value)
+	case len(ids) == 0:
+		return "", fmt.Errorf("no project found with %s %q", field, value)
+	default:
+		return ids[0], nil
+	}
 }
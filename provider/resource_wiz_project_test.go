@@ -0,0 +1,302 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"shell.com/terraform-provider-wiz/apiClient"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func accountLink(guid string, environment string) apiClient.CloudAccountLinkDetails {
+	return apiClient.CloudAccountLinkDetails{
+		CloudAccount: apiClient.CloudAccount{ID: guid},
+		Environment:  environment,
+	}
+}
+
+// describeAccountLinks renders links as "guid/environment" for comparison.
+func describeAccountLinks(links []CloudAccountLinkTypeData) string {
+	var described []string
+	for _, link := range links {
+		environment := "<nil>"
+		if link.Environment != nil {
+			environment = *link.Environment
+		}
+		described = append(described, fmt.Sprintf("%s/%s", link.GUID, environment))
+	}
+	return strings.Join(described, ", ")
+}
+
+func TestSetAccountLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []CloudAccountLinkTypeData
+		api     []apiClient.CloudAccountLinkDetails
+		want    string
+		wantNil bool
+	}{
+		{
+			name:    "state order kept",
+			current: []CloudAccountLinkTypeData{{GUID: "b"}, {GUID: "a"}},
+			api:     []apiClient.CloudAccountLinkDetails{accountLink("a", "PRODUCTION"), accountLink("b", "PRODUCTION")},
+			want:    "b/PRODUCTION, a/PRODUCTION",
+		},
+		{
+			name:    "unset environment defaults to production",
+			current: []CloudAccountLinkTypeData{{GUID: "a"}},
+			api:     []apiClient.CloudAccountLinkDetails{accountLink("a", "")},
+			want:    "a/PRODUCTION",
+		},
+		{
+			name: "same account matched per environment",
+			current: []CloudAccountLinkTypeData{
+				{GUID: "a", Environment: stringPtr("STAGING")},
+				{GUID: "a", Environment: stringPtr("PRODUCTION")},
+			},
+			api:  []apiClient.CloudAccountLinkDetails{accountLink("a", "PRODUCTION"), accountLink("a", "STAGING")},
+			want: "a/STAGING, a/PRODUCTION",
+		},
+		{
+			name:    "changed environment matched on account",
+			current: []CloudAccountLinkTypeData{{GUID: "a", Environment: stringPtr("DEVELOPMENT")}, {GUID: "b"}},
+			api:     []apiClient.CloudAccountLinkDetails{accountLink("b", "PRODUCTION"), accountLink("a", "STAGING")},
+			want:    "a/STAGING, b/PRODUCTION",
+		},
+		{
+			name:    "links added outside of terraform appended in api order",
+			current: []CloudAccountLinkTypeData{{GUID: "b"}},
+			api:     []apiClient.CloudAccountLinkDetails{accountLink("d", "PRODUCTION"), accountLink("b", "PRODUCTION"), accountLink("c", "TESTING")},
+			want:    "b/PRODUCTION, d/PRODUCTION, c/TESTING",
+		},
+		{
+			name:    "links removed outside of terraform dropped",
+			current: []CloudAccountLinkTypeData{{GUID: "a"}, {GUID: "b"}},
+			api:     []apiClient.CloudAccountLinkDetails{accountLink("b", "PRODUCTION")},
+			want:    "b/PRODUCTION",
+		},
+		{
+			name:    "null stays null",
+			current: nil,
+			api:     nil,
+			wantNil: true,
+		},
+		{
+			name:    "empty stays empty",
+			current: []CloudAccountLinkTypeData{},
+			api:     nil,
+		},
+		{
+			name:    "every link removed outside of terraform",
+			current: []CloudAccountLinkTypeData{{GUID: "a"}},
+			api:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := wizProjectTypeData{CloudAccountLinks: tt.current}
+			data.setAccountLinks(context.Background(), tt.api)
+
+			if got := describeAccountLinks(data.CloudAccountLinks); got != tt.want {
+				t.Errorf("setAccountLinks() = %q, want %q", got, tt.want)
+			}
+			if (data.CloudAccountLinks == nil) != tt.wantNil {
+				t.Errorf("setAccountLinks() returned nil = %t, want %t", data.CloudAccountLinks == nil, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestSetAccountLinksKeepsNullLists(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       CloudAccountLinkTypeData
+		api           apiClient.CloudAccountLinkDetails
+		wantTags      int
+		wantTagsNil   bool
+		wantGroups    int
+		wantGroupsNil bool
+	}{
+		{
+			name:          "null lists kept when the api returns none",
+			current:       CloudAccountLinkTypeData{GUID: "a"},
+			api:           accountLink("a", "PRODUCTION"),
+			wantTagsNil:   true,
+			wantGroupsNil: true,
+		},
+		{
+			name:    "empty lists kept when the api returns none",
+			current: CloudAccountLinkTypeData{GUID: "a", ResourceTags: []ResourceTagTypeData{}, ResourceGroups: []string{}},
+			api:     accountLink("a", "PRODUCTION"),
+		},
+		{
+			name:    "lists removed outside of terraform",
+			current: CloudAccountLinkTypeData{GUID: "a", ResourceTags: []ResourceTagTypeData{{Key: "team", Value: "x"}}, ResourceGroups: []string{"rg"}},
+			api:     accountLink("a", "PRODUCTION"),
+		},
+		{
+			name:    "lists read from the api",
+			current: CloudAccountLinkTypeData{GUID: "a"},
+			api: apiClient.CloudAccountLinkDetails{
+				CloudAccount:   apiClient.CloudAccount{ID: "a"},
+				ResourceTags:   []apiClient.ResourceTag{{Key: "team", Value: "x"}, {Key: "env", Value: "y"}},
+				ResourceGroups: []string{"rg"},
+			},
+			wantTags:   2,
+			wantGroups: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := wizProjectTypeData{CloudAccountLinks: []CloudAccountLinkTypeData{tt.current}}
+			data.setAccountLinks(context.Background(), []apiClient.CloudAccountLinkDetails{tt.api})
+
+			link := data.CloudAccountLinks[0]
+			if len(link.ResourceTags) != tt.wantTags || (link.ResourceTags == nil) != tt.wantTagsNil {
+				t.Errorf("resource_tags = %#v, want %d tags, nil %t", link.ResourceTags, tt.wantTags, tt.wantTagsNil)
+			}
+			if len(link.ResourceGroups) != tt.wantGroups || (link.ResourceGroups == nil) != tt.wantGroupsNil {
+				t.Errorf("resource_groups = %#v, want %d groups, nil %t", link.ResourceGroups, tt.wantGroups, tt.wantGroupsNil)
+			}
+		})
+	}
+}
+
+func TestOrderLinksRepositories(t *testing.T) {
+	current := []RepositoryLinkTypeData{
+		{VCSConnectorID: stringPtr("c"), RepositoryFullName: stringPtr("org/b")},
+		{VCSConnectorID: stringPtr("c"), RepositoryFullName: stringPtr("org/a")},
+	}
+	current[0].RepositoryID.Value = "repo-b"
+	current[1].RepositoryID.Value = "repo-a"
+
+	data := wizProjectTypeData{RepositoryLinks: current}
+	data.setRepositoryLinks(context.Background(), []apiClient.RepositoryLinkDetails{
+		{Repository: apiClient.Repository{ID: "repo-a"}},
+		{Repository: apiClient.Repository{ID: "repo-c"}},
+		{Repository: apiClient.Repository{ID: "repo-b"}},
+	})
+
+	want := []struct {
+		id       string
+		fullName *string
+	}{
+		{"repo-b", stringPtr("org/b")},
+		{"repo-a", stringPtr("org/a")},
+		{"repo-c", nil},
+	}
+	if len(data.RepositoryLinks) != len(want) {
+		t.Fatalf("setRepositoryLinks() returned %d links, want %d", len(data.RepositoryLinks), len(want))
+	}
+	for i, w := range want {
+		link := data.RepositoryLinks[i]
+		if link.RepositoryID.Value != w.id {
+			t.Errorf("link %d repository_id = %q, want %q", i, link.RepositoryID.Value, w.id)
+		}
+		if (link.RepositoryFullName == nil) != (w.fullName == nil) ||
+			(w.fullName != nil && *link.RepositoryFullName != *w.fullName) {
+			t.Errorf("link %d repository_full_name = %v, want %v", i, link.RepositoryFullName, w.fullName)
+		}
+	}
+}
+
+func TestUsersOrPrevious(t *testing.T) {
+	users := []apiClient.User{
+		{ID: "u1", Email: "jane.doe@example.com"},
+		{ID: "u2", Email: "john.doe@example.com"},
+	}
+
+	tests := []struct {
+		name     string
+		users    []apiClient.User
+		previous []string
+		want     []string
+	}{
+		{"null kept", nil, nil, nil},
+		{"empty kept", nil, []string{}, []string{}},
+		{"named by id", users, []string{"u1", "u2"}, []string{"u1", "u2"}},
+		{"named by email ignoring case", users, []string{"Jane.Doe@example.com", "u2"}, []string{"Jane.Doe@example.com", "u2"}},
+		{"added outside of terraform named by id", users, []string{"jane.doe@example.com"}, []string{"jane.doe@example.com", "u2"}},
+		{"removed outside of terraform", nil, []string{"u1"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := usersOrPrevious(tt.users, tt.previous)
+			if (got == nil) != (tt.want == nil) || strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("usersOrPrevious() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadProjectProperties(t *testing.T) {
+	known := types.String{Value: "known"}
+	unknown := types.String{Unknown: true}
+	null := types.String{Null: true}
+
+	tests := []struct {
+		name        string
+		refresh     bool
+		slug        types.String
+		updatedAt   types.String
+		wantSearch  bool
+		wantSlug    string
+		wantUpdated string
+	}{
+		{"refresh with known properties", true, known, known, false, "known", "known"},
+		{"refresh after import", true, null, null, true, "my-project", "2026-01-01T00:00:00Z"},
+		{"update planning updated_at unknown", false, known, unknown, true, "known", "2026-01-01T00:00:00Z"},
+		{"update keeping the planned properties", false, known, known, false, "known", "known"},
+		{"create", false, unknown, unknown, true, "my-project", "2026-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searches := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/oauth/token" {
+					json.NewEncoder(w).Encode(apiClient.TokenResponse{AccessToken: "token", ExpiresIn: 3600})
+					return
+				}
+				searches++
+				w.Write([]byte(`{"data":{"graphSearch":{"nodes":[{"entities":[{"id":"p1","type":"PROJECT",` +
+					`"properties":{"slug":"my-project","externalId":"ext","updatedAt":"2026-01-01T00:00:00Z"}}]}]}}}`))
+			}))
+			defer server.Close()
+
+			client, err := apiClient.CreateClient(context.Background(), apiClient.ClientConfig{
+				Credentials: apiClient.ClientCredentials{
+					ClientID:     "id",
+					ClientSecret: "secret",
+					Endpoint:     server.URL + "/graphql",
+					AuthURL:      server.URL + "/oauth/token",
+				},
+			})
+			if err != nil {
+				t.Fatalf("CreateClient() error = %s", err)
+			}
+
+			id := "p1"
+			data := wizProjectTypeData{ID: &id, Slug: tt.slug, ExternalID: tt.slug, UpdatedAt: tt.updatedAt}
+			var diags diag.Diagnostics
+			wizProject{provider: provider{wizClient: client}}.readProjectProperties(context.Background(), &data, tt.refresh, &diags)
+
+			if diags.HasError() || (searches > 0) != tt.wantSearch {
+				t.Errorf("readProjectProperties() searched the graph %d times with diagnostics %v, want search %t", searches, diags, tt.wantSearch)
+			}
+			if data.Slug.Value != tt.wantSlug || data.UpdatedAt.Value != tt.wantUpdated {
+				t.Errorf("readProjectProperties() set slug %q and updated_at %q, want %q and %q",
+					data.Slug.Value, data.UpdatedAt.Value, tt.wantSlug, tt.wantUpdated)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const defaultTimeout = 20 * time.Minute
+
+type TimeoutsTypeData struct {
+	Create *string `tfsdk:"create"`
+	Read   *string `tfsdk:"read"`
+	Update *string `tfsdk:"update"`
+	Delete *string `tfsdk:"delete"`
+}
+
+// timeoutsAttribute returns the schema of the timeouts attribute shared by
+// all resources. Each operation accepts a duration string such as "30s" or
+// "20m".
+func timeoutsAttribute() tfsdk.Attribute {
+	timeout := func(operation string) tfsdk.Attribute {
+		return tfsdk.Attribute{
+			MarkdownDescription: "Timeout for " + operation + " operations, e.g. `30s` or `20m`. Defaults to `20m`.",
+			Optional:            true,
+			Type:                types.StringType,
+			Validators: []tfsdk.AttributeValidator{
+				durationValidator{},
+			},
+		}
+	}
+
+	return tfsdk.Attribute{
+		MarkdownDescription: "Timeouts of the resource operations.",
+		Optional:            true,
+		Attributes: tfsdk.SingleNestedAttributes(
+			map[string]tfsdk.Attribute{
+				"create": timeout("create"),
+				"read":   timeout("read"),
+				"update": timeout("update"),
+				"delete": timeout("delete"),
+			},
+		),
+	}
+}
+
+// withTimeout derives a context bounded by the configured timeout of an
+// operation, or by defaultTimeout when none is configured. The value has
+// already been checked by durationValidator.
+func withTimeout(ctx context.Context, configured *string) (context.Context, context.CancelFunc) {
+	timeout := defaultTimeout
+	if configured != nil {
+		if d, err := time.ParseDuration(*configured); err == nil {
+			timeout = d
+		}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (t *TimeoutsTypeData) create() *string {
+	if t == nil {
+		return nil
+	}
+	return t.Create
+}
+
+func (t *TimeoutsTypeData) read() *string {
+	if t == nil {
+		return nil
+	}
+	return t.Read
+}
+
+func (t *TimeoutsTypeData) update() *string {
+	if t == nil {
+		return nil
+	}
+	return t.Update
+}
+
+func (t *TimeoutsTypeData) delete() *string {
+	if t == nil {
+		return nil
+	}
+	return t.Delete
+}
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// stringInSliceValidator is an attribute validator that checks a
+// types.StringType attribute, or every element of a list of strings, against
+// a fixed set of allowed values. Null and unknown values are skipped.
+type stringInSliceValidator struct {
+	Values []string
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v stringInSliceValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("Value must be one of: %s", strings.Join(v.Values, ", "))
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v stringInSliceValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Value must be one of: `%s`", strings.Join(v.Values, "`, `"))
+}
+
+// Validate runs the logic of the validator.
+func (v stringInSliceValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	type pathValue struct {
+		path  *tftypes.AttributePath
+		value types.String
+	}
+	var values []pathValue
+
+	switch val := req.AttributeConfig.(type) {
+	case types.List:
+		if val.Null || val.Unknown {
+			return
+		}
+		for i, elem := range val.Elems {
+			str, ok := elem.(types.String)
+			if !ok {
+				resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid Attribute Type",
+					fmt.Sprintf("Expected a list of strings, got an element of type %T.", elem))
+				return
+			}
+			values = append(values, pathValue{req.AttributePath.WithElementKeyInt(i), str})
+		}
+	default:
+		var str types.String
+		diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &str)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+		values = append(values, pathValue{req.AttributePath, str})
+	}
+
+	for _, pv := range values {
+		if pv.value.Null || pv.value.Unknown || v.contains(pv.value.Value) {
+			continue
+		}
+		resp.Diagnostics.AddAttributeError(pv.path, "Invalid Attribute Value",
+			fmt.Sprintf("%q is not a valid value. %s.", pv.value.Value, v.Description(ctx)))
+	}
+}
+
+func (v stringInSliceValidator) contains(value string) bool {
+	for _, allowed := range v.Values {
+		if value == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// urlValidator is an attribute validator that checks a types.StringType
+// attribute is an absolute http(s) URL.
+type urlValidator struct{}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v urlValidator) Description(ctx context.Context) string {
+	return "Value must be an absolute http or https URL"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v urlValidator) MarkdownDescription(ctx context.Context) string {
+	return "Value must be an absolute `http` or `https` URL"
+}
+
+// Validate runs the logic of the validator.
+func (v urlValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &str)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || str.Null || str.Unknown {
+		return
+	}
+
+	u, err := url.Parse(str.Value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid Attribute Value",
+			fmt.Sprintf("%q is not a valid URL. %s.", str.Value, v.Description(ctx)))
+	}
+}
+
+// int64AtLeastValidator is an attribute validator that checks a
+// types.Int64Type attribute is not below a minimum.
+type int64AtLeastValidator struct {
+	Min int64
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v int64AtLeastValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("Value must be at least %d", v.Min)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v int64AtLeastValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Value must be at least `%d`", v.Min)
+}
+
+// Validate runs the logic of the validator.
+func (v int64AtLeastValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var value types.Int64
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &value)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || value.Null || value.Unknown {
+		return
+	}
+
+	if value.Value < v.Min {
+		resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid Attribute Value",
+			fmt.Sprintf("%d is not a valid value. %s.", value.Value, v.Description(ctx)))
+	}
+}
+
+// durationValidator is an attribute validator that checks a types.StringType
+// attribute is a positive duration as understood by time.ParseDuration.
+type durationValidator struct{}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v durationValidator) Description(ctx context.Context) string {
+	return "Value must be a positive duration such as 30s or 20m"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "Value must be a positive duration such as `30s` or `20m`"
+}
+
+// Validate runs the logic of the validator.
+func (v durationValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &str)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || str.Null || str.Unknown {
+		return
+	}
+
+	if d, err := time.ParseDuration(str.Value); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid Attribute Value",
+			fmt.Sprintf("%q is not a valid duration. %s.", str.Value, v.Description(ctx)))
+	}
+}
+
+// dataCenterPattern matches the name of a Wiz data center, e.g. us17, which
+// becomes part of the API endpoint.
+var dataCenterPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// dataCenterValidator is an attribute validator that checks a
+// types.StringType attribute is a data center name.
+type dataCenterValidator struct{}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v dataCenterValidator) Description(ctx context.Context) string {
+	return "Value must only contain lowercase letters, digits and hyphens, such as us17 or eu1"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v dataCenterValidator) MarkdownDescription(ctx context.Context) string {
+	return "Value must only contain lowercase letters, digits and hyphens, such as `us17` or `eu1`"
+}
+
+// Validate runs the logic of the validator.
+func (v dataCenterValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &str)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || str.Null || str.Unknown {
+		return
+	}
+
+	if !dataCenterPattern.MatchString(str.Value) {
+		resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid Attribute Value",
+			fmt.Sprintf("%q is not a valid data center. %s.", str.Value, v.Description(ctx)))
+	}
+}
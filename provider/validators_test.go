@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDataCenterValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   types.String
+		wantErr bool
+	}{
+		{"data center", types.String{Value: "us17"}, false},
+		{"with hyphen", types.String{Value: "us-gov1"}, false},
+		{"null", types.String{Null: true}, false},
+		{"unknown", types.String{Unknown: true}, false},
+		{"uppercase", types.String{Value: "US17"}, true},
+		{"empty", types.String{Value: ""}, true},
+		{"url", types.String{Value: "api.us17.app.wiz.io/graphql"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tftypes.NewAttributePath().WithAttributeName("data_center")
+			resp := &tfsdk.ValidateAttributeResponse{}
+			dataCenterValidator{}.Validate(context.Background(), tfsdk.ValidateAttributeRequest{
+				AttributePath:   path,
+				AttributeConfig: tt.value,
+			}, resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("Validate() diagnostics = %v, want error %t", resp.Diagnostics, tt.wantErr)
+			}
+			for _, d := range resp.Diagnostics {
+				if withPath, ok := d.(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(path) {
+					t.Errorf("Validate() diagnostic %v is not reported on %s", d, path)
+				}
+			}
+		})
+	}
+}